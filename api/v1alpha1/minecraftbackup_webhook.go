@@ -0,0 +1,109 @@
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (b *MinecraftBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(b).
+		WithValidator(&minecraftBackupValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-minecraft-jameslaverack-com-v1alpha1-minecraftbackup,mutating=false,failurePolicy=fail,sideEffects=None,groups=minecraft.jameslaverack.com,resources=minecraftbackups,verbs=create;update,versions=v1alpha1,name=vminecraftbackup.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MinecraftBackup{}
+
+// ValidateCreate checks the backup has somewhere to back up from and to.
+func (b *MinecraftBackup) ValidateCreate() error {
+	return b.invalid(b.validateSpec())
+}
+
+// ValidateUpdate rejects any change to the spec. A backup is taken once, so changing it afterwards would make it lie
+// about what it contains.
+func (b *MinecraftBackup) ValidateUpdate(old runtime.Object) error {
+	errs := b.validateSpec()
+	if oldBackup, ok := old.(*MinecraftBackup); ok && !apiequality.Semantic.DeepEqual(b.Spec, oldBackup.Spec) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec"), "is immutable"))
+	}
+	return b.invalid(errs)
+}
+
+// ValidateDelete allows all deletes.
+func (b *MinecraftBackup) ValidateDelete() error {
+	return nil
+}
+
+// minecraftBackupValidator does the same checks as MinecraftBackup's own validation, and also checks the server that's
+// being backed up, which needs a client.
+type minecraftBackupValidator struct {
+	client client.Reader
+}
+
+var _ admission.CustomValidator = &minecraftBackupValidator{}
+
+func (v *minecraftBackupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	b, ok := obj.(*MinecraftBackup)
+	if !ok {
+		return errors.Errorf("expected a MinecraftBackup, not %T", obj)
+	}
+	errs := b.validateSpec()
+	if b.Spec.Server.Name != "" {
+		var server MinecraftServer
+		err := v.client.Get(ctx, client.ObjectKey{Name: b.Spec.Server.Name, Namespace: b.Namespace}, &server)
+		if client.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, "failed to get the MinecraftServer to back up")
+		}
+		// Backups pause saving over RCON while they copy the world
+		if err == nil && server.Spec.Type == ServerTypeBedrock {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "server", "name"),
+				"Bedrock servers don't have RCON, so they can't be backed up"))
+		}
+	}
+	return b.invalid(errs)
+}
+
+// ValidateUpdate doesn't need to check the server again, as the spec can't be changed.
+func (v *minecraftBackupValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	b, ok := newObj.(*MinecraftBackup)
+	if !ok {
+		return errors.Errorf("expected a MinecraftBackup, not %T", newObj)
+	}
+	return b.ValidateUpdate(oldObj)
+}
+
+func (v *minecraftBackupValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+func (b *MinecraftBackup) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("MinecraftBackup").GroupKind(), b.Name, errs)
+}
+
+func (b *MinecraftBackup) validateSpec() field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	if b.Spec.Server.Name == "" {
+		errs = append(errs, field.Required(spec.Child("server", "name"), "must name the MinecraftServer to back up"))
+	}
+	if b.Spec.BackupDestination == nil {
+		errs = append(errs, field.Required(spec.Child("backupDestination"), "must be set to store the backup"))
+	} else if b.Spec.BackupDestination.ClaimName == "" {
+		errs = append(errs, field.Required(spec.Child("backupDestination", "claimName"), ""))
+	}
+	return errs
+}
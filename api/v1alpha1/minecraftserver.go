@@ -4,18 +4,47 @@ package v1alpha1
 //go:generate controller-gen object
 
 import (
+	"encoding/json"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/version"
 )
 
-// +kubebuilder:validation:Enum=Paper;Forge
+// +kubebuilder:validation:Enum=Paper;Forge;Vanilla;Fabric;Folia;Purpur;NeoForge;Bedrock
 type ServerType string
 
 const (
-	ServerTypePaper ServerType = "Paper"
-	ServerTypeForge ServerType = "Forge"
+	ServerTypePaper    ServerType = "Paper"
+	ServerTypeForge    ServerType = "Forge"
+	ServerTypeVanilla  ServerType = "Vanilla"
+	ServerTypeFabric   ServerType = "Fabric"
+	ServerTypeFolia    ServerType = "Folia"
+	ServerTypePurpur   ServerType = "Purpur"
+	ServerTypeNeoForge ServerType = "NeoForge"
+	ServerTypeBedrock  ServerType = "Bedrock"
 )
 
+// serverTypes are all the server types, in the same order as the enum above.
+var serverTypes = []ServerType{
+	ServerTypePaper, ServerTypeForge, ServerTypeVanilla, ServerTypeFabric,
+	ServerTypeFolia, ServerTypePurpur, ServerTypeNeoForge, ServerTypeBedrock,
+}
+
+// IsPaperBased is true for servers that support Bukkit plugins, which also keep the Nether and the End as separate
+// worlds rather than inside the main world directory.
+func (t ServerType) IsPaperBased() bool {
+	return t == ServerTypePaper || t == ServerTypeFolia || t == ServerTypePurpur
+}
+
+// IsForgeBased is true for servers that are set up with an installer and run with modpacks, which keep everything in
+// their working directory.
+func (t ServerType) IsForgeBased() bool {
+	return t == ServerTypeForge || t == ServerTypeNeoForge
+}
+
 // +kubebuilder:validation:Enum=Accepted;NotAccepted
 type EULAAcceptance string
 
@@ -31,10 +60,128 @@ type ForgeSpec struct {
 	ModpackZipSHA256Sum     string `json:"modpackZipSha256Sum"`
 }
 
+// NeoForgeSpec configures a NeoForge server.
+type NeoForgeSpec struct {
+	// NeoForgeVersion is the version of NeoForge, e.g., "20.4.237". Defaults to the latest version for the Minecraft
+	// version, preferring stable versions to betas. Once chosen, the default isn't changed until the Minecraft version
+	// is.
+	// +optional
+	NeoForgeVersion string `json:"neoForgeVersion,omitempty"`
+	// InstallerSHA256Sum is the checksum of the NeoForge installer. If set, the download is checked against it.
+	// +optional
+	InstallerSHA256Sum string `json:"installerSha256Sum,omitempty"`
+	// ModpackZipURL is a ZIP file that's extracted into the server's working directory, e.g., a modpack's server
+	// files.
+	// +optional
+	ModpackZipURL string `json:"modpackZipUrl,omitempty"`
+	// ModpackZipSHA256Sum is the checksum of the modpack ZIP file. If set, the download is checked against it.
+	// +optional
+	ModpackZipSHA256Sum string `json:"modpackZipSha256Sum,omitempty"`
+}
+
+// BedrockSpec configures a Bedrock Dedicated Server.
+type BedrockSpec struct {
+	// DownloadURL is the Bedrock Dedicated Server ZIP file for Linux. Defaults to Mojang's download for the Minecraft
+	// version, which is a Bedrock version like "1.21.2.02". Mojang don't document that download, so it may change.
+	// +optional
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	// DownloadSHA256Sum is the checksum of the ZIP file. If set, the download is checked against it.
+	// +optional
+	DownloadSHA256Sum string `json:"downloadSha256Sum,omitempty"`
+	// Image is the container image to run the server in. The server is a Linux binary that needs glibc and libcurl.
+	// Defaults to "buildpack-deps:jammy-curl".
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// FabricSpec configures a Fabric server.
+type FabricSpec struct {
+	// LoaderVersion is the version of the Fabric loader, e.g., "0.15.6". Defaults to the latest stable version for the
+	// Minecraft version. Once chosen, the default isn't changed until the Minecraft version is.
+	// +optional
+	LoaderVersion string `json:"loaderVersion,omitempty"`
+	// InstallerVersion is the version of the Fabric installer used to build the server launcher, e.g., "1.0.0".
+	// Defaults to the latest stable version.
+	// +optional
+	InstallerVersion string `json:"installerVersion,omitempty"`
+	// Mods to download into the server's mods directory. Most mods also need the Fabric API mod.
+	// +optional
+	Mods []ModSpec `json:"mods,omitempty"`
+}
+
+// PaperChannel is a release channel of PaperMC builds.
+// +kubebuilder:validation:Enum=Default;Experimental
+type PaperChannel string
+
+const (
+	PaperChannelDefault      PaperChannel = "Default"
+	PaperChannelExperimental PaperChannel = "Experimental"
+)
+
+// PaperSpec configures a Paper or Folia server.
+type PaperSpec struct {
+	// Channel is the least stable release channel that builds are taken from. Default only runs builds that PaperMC
+	// considers stable, while Experimental also runs early builds, which are often the only builds for a new Minecraft
+	// version. Defaults to Default for Paper, and to Experimental for Folia as most Folia builds are experimental.
+	// +optional
+	Channel PaperChannel `json:"channel,omitempty"`
+}
+
+// PurpurSpec configures purpur.yml for a Purpur server.
+type PurpurSpec struct {
+	// UseAlternateKeepalive sends keepalive packets once a second, rather than every 15 seconds, which stops players on
+	// unreliable connections from being kicked as often.
+	// +optional
+	UseAlternateKeepalive *bool `json:"useAlternateKeepalive,omitempty"`
+	// UseBetterMending makes mending repair the most damaged item, rather than a random one.
+	// +optional
+	UseBetterMending *bool `json:"useBetterMending,omitempty"`
+	// SilkTouchSpawners allows spawners to be mined with silk touch.
+	// +optional
+	SilkTouchSpawners *bool `json:"silkTouchSpawners,omitempty"`
+	// LobotomizeVillagers stops villagers that can't move from pathfinding, which is a common cause of lag with
+	// trading halls.
+	// +optional
+	LobotomizeVillagers *bool `json:"lobotomizeVillagers,omitempty"`
+	// Settings are written to purpur.yml, and take precedence over any of the typed fields above. Keys are dotted
+	// paths, e.g., "world-settings.default.gameplay-mechanics.player.idle-timeout.kick-if-idle", and values are
+	// parsed as YAML, so "false" is a boolean and "5" is a number.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// TypedSettings are the settings from the typed fields, keyed by their dotted path in purpur.yml. Fields that aren't
+// set are left out, so Purpur uses its own default.
+func (s *PurpurSpec) TypedSettings() map[string]interface{} {
+	settings := make(map[string]interface{})
+	setBool := func(path string, b *bool) {
+		if b != nil {
+			settings[path] = *b
+		}
+	}
+	setBool("settings.use-alternate-keepalive", s.UseAlternateKeepalive)
+	setBool("world-settings.default.gameplay-mechanics.use-better-mending", s.UseBetterMending)
+	setBool("world-settings.default.gameplay-mechanics.silk-touch.enabled", s.SilkTouchSpawners)
+	setBool("world-settings.default.mobs.villager.lobotomize.enabled", s.LobotomizeVillagers)
+	return settings
+}
+
+// ModSpec is a mod to download.
+type ModSpec struct {
+	// URL of the mod's JAR file.
+	URL string `json:"url"`
+	// SHA256Sum of the JAR file. If set, the download is checked against it.
+	// +optional
+	SHA256Sum string `json:"sha256Sum,omitempty"`
+}
+
 // Player is a Minecraft player defined by a username or a UUID
 type Player struct {
 	Name string `json:"name,omitempty"`
 	UUID string `json:"uuid,omitempty"`
+	// XUID is the player's Xbox Live user ID, which Bedrock servers use to identify players instead of a UUID.
+	// +optional
+	XUID string `json:"xuid,omitempty"`
 }
 
 type WorldSpec struct {
@@ -69,12 +216,28 @@ type DynmapSpec struct {
 }
 
 // +kubebuilder:validation:Enum=Survival;Creative
-// +kubebuilder:default:=Survial
+// +kubebuilder:default:=Survival
 type GameMode string
 
 const GameModeSurvival GameMode = "Survival"
 const GameModeCreative GameMode = "Creative"
 
+// +kubebuilder:validation:Enum=Peaceful;Easy;Normal;Hard
+type Difficulty string
+
+const DifficultyPeaceful Difficulty = "Peaceful"
+const DifficultyEasy Difficulty = "Easy"
+const DifficultyNormal Difficulty = "Normal"
+const DifficultyHard Difficulty = "Hard"
+
+// +kubebuilder:validation:Enum=Normal;Flat;LargeBiomes;Amplified
+type LevelType string
+
+const LevelTypeNormal LevelType = "Normal"
+const LevelTypeFlat LevelType = "Flat"
+const LevelTypeLargeBiomes LevelType = "LargeBiomes"
+const LevelTypeAmplified LevelType = "Amplified"
+
 // +kubebuilder:validation:Enum=Public;AllowListOnly
 // +kubebuilder:default:=AllowListOnly
 type AccessMode string
@@ -93,13 +256,303 @@ type MinecraftServerSpec struct {
 	World            *WorldSpec      `json:"world,omitempty"`
 	MOTD             string          `json:"motd"`
 	GameMode         GameMode        `json:"gameMode"`
-	MaxPlayers       int             `json:"maxPlayers"`
-	ViewDistance     int             `json:"viewDistance"`
-	Service          *ServiceSpec    `json:"service"`
 	VanillaTweaks    *VanillaTweaks  `json:"vanillaTweaks,omitempty"`
 	Monitoring       *MonitoringSpec `json:"monitoring,omitempty"`
 	Dynmap           *DynmapSpec     `json:"dynmap,omitempty"`
 	Forge            *ForgeSpec      `json:"forge,omitempty"`
+	Shutdown         *ShutdownSpec   `json:"shutdown,omitempty"`
+	// MaxPlayers is the most players that can be online at once. Defaults to 20.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPlayers *int32 `json:"maxPlayers,omitempty"`
+	// ViewDistance is how far, in chunks, the server sends to players. Defaults to 10.
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	ViewDistance *int32 `json:"viewDistance,omitempty"`
+	// Service exposes the server to players. If this isn't set then no Service is created.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+	// Resources for the server's container. Defaults to a size suitable for the server type, as modded servers need
+	// more memory than plugin servers.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// JVM configures the Java Virtual Machine the server runs in.
+	// +optional
+	JVM *JVMSpec `json:"jvm,omitempty"`
+	// Difficulty of the game. Defaults to Minecraft's own default, which is Easy.
+	// +optional
+	Difficulty Difficulty `json:"difficulty,omitempty"`
+	// Hardcore sets the difficulty to Hard, and players are put in spectator mode when they die.
+	// +optional
+	Hardcore *bool `json:"hardcore,omitempty"`
+	// PVP allows players to damage each other.
+	// +optional
+	PVP *bool `json:"pvp,omitempty"`
+	// SimulationDistance is how far, in chunks, from players the world is ticked.
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	SimulationDistance *int32 `json:"simulationDistance,omitempty"`
+	// SpawnProtection is the radius, in blocks, around spawn that only ops can change. Zero disables spawn protection.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SpawnProtection *int32 `json:"spawnProtection,omitempty"`
+	// LevelType is the kind of world to generate. This only has an effect when the world is first created.
+	// +optional
+	LevelType LevelType `json:"levelType,omitempty"`
+	// AllowFlight stops players being kicked for flying. Some mods and plugins need this.
+	// +optional
+	AllowFlight *bool `json:"allowFlight,omitempty"`
+	// OnlineMode checks that players are logged in to a Minecraft account. This should only be disabled if the server
+	// is behind a proxy that does this instead.
+	// +optional
+	OnlineMode *bool `json:"onlineMode,omitempty"`
+	// ServerProperties are written to server.properties as-is, and take precedence over any of the typed fields above.
+	// Keys must be ones that Minecraft understands. Keys that the operator relies on (such as server-port and the RCON
+	// settings) can't be set here.
+	// +optional
+	ServerProperties map[string]string `json:"serverProperties,omitempty"`
+	// PodTemplate is merged into the Pod template the operator generates for the server, in the same way as kubectl
+	// apply. Containers are merged by name, so fields can be added to the server's own container (called "minecraft")
+	// as well as adding sidecar containers.
+	// +optional
+	PodTemplate *PodTemplate `json:"podTemplate,omitempty"`
+	// Workload configures what kind of workload runs the server's Pod, and the storage it provisions.
+	// +optional
+	Workload *WorkloadSpec `json:"workload,omitempty"`
+	// Persistence keeps more of the server's state than just the world, using existing PersistentVolumeClaims.
+	// +optional
+	Persistence *PersistenceSpec `json:"persistence,omitempty"`
+	// Fabric configures Fabric servers. Only used if the type is Fabric.
+	// +optional
+	Fabric *FabricSpec `json:"fabric,omitempty"`
+	// Paper configures Paper and Folia servers. Only used if the type is Paper or Folia.
+	// +optional
+	Paper *PaperSpec `json:"paper,omitempty"`
+	// Purpur configures Purpur servers. Only used if the type is Purpur.
+	// +optional
+	Purpur *PurpurSpec `json:"purpur,omitempty"`
+	// NeoForge configures NeoForge servers. Only used if the type is NeoForge.
+	// +optional
+	NeoForge *NeoForgeSpec `json:"neoForge,omitempty"`
+	// Bedrock configures Bedrock servers. Only used if the type is Bedrock.
+	// +optional
+	Bedrock *BedrockSpec `json:"bedrock,omitempty"`
+}
+
+// PersistenceSpec gives PersistentVolumeClaims to keep the server's state in. Anything not given is lost when the
+// server restarts.
+type PersistenceSpec struct {
+	// PluginData is a claim to keep the plugins directory in, where plugins keep their data. Plugin JARs installed by
+	// the operator are replaced each time the server starts, and removed if they're no longer enabled. Paper servers
+	// only.
+	// +optional
+	PluginData *corev1.PersistentVolumeClaimVolumeSource `json:"pluginData,omitempty"`
+	// ServerState is a claim to keep the server's working directory in. This has files like usercache.json,
+	// banned-players.json, and Paper's config. Files that the operator manages, like server.properties and the allow
+	// list, are replaced each time the server starts.
+	// +optional
+	ServerState *corev1.PersistentVolumeClaimVolumeSource `json:"serverState,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=ReplicaSet;StatefulSet
+type WorkloadType string
+
+const WorkloadTypeReplicaSet WorkloadType = "ReplicaSet"
+const WorkloadTypeStatefulSet WorkloadType = "StatefulSet"
+
+// WorkloadSpec configures the workload that runs the server's Pod.
+type WorkloadSpec struct {
+	// Type of workload to run the server with. Either way, there is never more than one Pod running the server at a
+	// time. A StatefulSet can provision storage for the server itself. Defaults to ReplicaSet.
+	// +optional
+	Type WorkloadType `json:"type,omitempty"`
+	// Storage to provision for the server. Only supported by the StatefulSet workload type.
+	// +optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+}
+
+// StorageSpec configures the PersistentVolumeClaims that a StatefulSet provisions for the server. Each size that is set
+// provisions storage for that purpose, anything that isn't set is not persisted. The claims are kept if the
+// MinecraftServer is deleted.
+type StorageSpec struct {
+	// StorageClassName of the claims. Defaults to the cluster's default StorageClass.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// World is the size of the world. On Paper servers, the overworld, Nether, and End are each given a claim of this
+	// size. Can't be used together with spec.world.
+	// +optional
+	World *resource.Quantity `json:"world,omitempty"`
+	// PluginData is the size of the plugins directory, where plugins keep their data. Paper servers only. Can't be used
+	// together with spec.persistence.pluginData.
+	// +optional
+	PluginData *resource.Quantity `json:"pluginData,omitempty"`
+	// Dynmap is the size of Dynmap's map storage. Can't be used together with spec.dynmap.persistentVolumeClaim.
+	// +optional
+	Dynmap *resource.Quantity `json:"dynmap,omitempty"`
+	// ServerState is the size of the server's working directory. Can't be used together with
+	// spec.persistence.serverState.
+	// +optional
+	ServerState *resource.Quantity `json:"serverState,omitempty"`
+}
+
+// PodTemplate is an overlay for the server's Pod template. The field names match those of a Pod.
+type PodTemplate struct {
+	// +optional
+	Metadata PodMetadata `json:"metadata,omitempty"`
+	// +optional
+	Spec PodTemplatePodSpec `json:"spec,omitempty"`
+}
+
+// PodMetadata is extra metadata for the server's Pod.
+type PodMetadata struct {
+	// Labels to add to the Pod. The labels the operator uses to find the Pod can't be changed.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations to add to the Pod.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PodTemplatePodSpec is the part of a PodSpec that can be overlaid onto the server's Pod. The schemas of the larger
+// fields aren't included in the CRD, as they'd make it too big to install with client-side apply. They're still
+// checked when they're decoded by the webhook and the operator.
+type PodTemplatePodSpec struct {
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// InitContainers are merged with the operator's init containers by name. New ones run after the operator's own.
+	// +optional
+	InitContainers []ContainerOverlay `json:"initContainers,omitempty"`
+	// Containers are merged with the server's container by name, and any others are added as sidecars.
+	// +optional
+	Containers []ContainerOverlay `json:"containers,omitempty"`
+	// Volumes are merged with the operator's volumes by name, for use by any added containers.
+	// +optional
+	Volumes []VolumeOverlay `json:"volumes,omitempty"`
+}
+
+// ContainerOverlay is a container in a PodTemplatePodSpec. It's encoded exactly like a corev1.Container.
+// +kubebuilder:validation:Type=object
+// +kubebuilder:pruning:PreserveUnknownFields
+type ContainerOverlay struct {
+	corev1.Container `json:"-"`
+}
+
+func (c ContainerOverlay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Container)
+}
+
+func (c *ContainerOverlay) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &c.Container)
+}
+
+// VolumeOverlay is a volume in a PodTemplatePodSpec. It's encoded exactly like a corev1.Volume.
+// +kubebuilder:validation:Type=object
+// +kubebuilder:pruning:PreserveUnknownFields
+type VolumeOverlay struct {
+	corev1.Volume `json:"-"`
+}
+
+func (v VolumeOverlay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Volume)
+}
+
+func (v *VolumeOverlay) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &v.Volume)
+}
+
+// +kubebuilder:validation:Enum=G1;ZGC;Shenandoah;Parallel
+type GarbageCollector string
+
+const GarbageCollectorG1 GarbageCollector = "G1"
+const GarbageCollectorZGC GarbageCollector = "ZGC"
+const GarbageCollectorShenandoah GarbageCollector = "Shenandoah"
+const GarbageCollectorParallel GarbageCollector = "Parallel"
+
+// +kubebuilder:validation:Enum=default;aikar;zgc-generational;shenandoah
+type JVMProfile string
+
+const JVMProfileDefault JVMProfile = "default"
+const JVMProfileAikar JVMProfile = "aikar"
+const JVMProfileZGCGenerational JVMProfile = "zgc-generational"
+const JVMProfileShenandoah JVMProfile = "shenandoah"
+
+// JVMSpec configures the Java Virtual Machine the server runs in.
+type JVMSpec struct {
+	// Profile is a named set of JVM tuning flags, tailored by the operator to the server's Java version and heap size.
+	// "aikar" is Aikar's G1 tuning for Minecraft servers, "zgc-generational" and "shenandoah" use low-pause garbage
+	// collectors, and "default" leaves everything but the heap size to the JVM. Defaults to "default".
+	// +optional
+	Profile JVMProfile `json:"profile,omitempty"`
+	// Image is the container image to run the server with, overriding the one the operator picks for the Minecraft
+	// version. It must have java on the PATH, and be a version of Java that can run the server.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// JavaVersion is the major version of Java in Image, e.g., 21. The profile's flags are tailored to it. Defaults to
+	// the version the operator would have picked for the Minecraft version. Can only be set if Image is.
+	// +kubebuilder:validation:Minimum=8
+	// +optional
+	JavaVersion *int32 `json:"javaVersion,omitempty"`
+	// Heap is the size of the Java heap. The minimum and maximum heap sizes are both set to this, as the server will
+	// use it all eventually anyway. If this isn't set, the heap is sized from HeapPercentage instead.
+	// +optional
+	Heap *resource.Quantity `json:"heap,omitempty"`
+	// HeapPercentage sizes the Java heap as a percentage of the container's memory limit (or request, if there is no
+	// limit). Some headroom is needed for memory the JVM uses outside of the heap. Defaults to 75.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=95
+	// +optional
+	HeapPercentage *int32 `json:"heapPercentage,omitempty"`
+	// GarbageCollector to use. Defaults to the JVM's own choice, which is G1 for any server-sized container. Profiles
+	// other than "default" choose a garbage collector themselves, so this can only be used with the "default" profile.
+	// +optional
+	GarbageCollector GarbageCollector `json:"garbageCollector,omitempty"`
+	// ExtraArgs are passed to the JVM after any generated by the operator, so they take precedence.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+}
+
+// JavaVersion is the major version of Java the server runs with.
+func (s *MinecraftServer) JavaVersion() int {
+	if jvm := s.Spec.JVM; jvm != nil && jvm.Image != "" && jvm.JavaVersion != nil {
+		return int(*jvm.JavaVersion)
+	}
+	return version.JavaVersion(s.Spec.MinecraftVersion)
+}
+
+// ShutdownSpec configures how the server is stopped when its Pod is terminated, for example when the server is being
+// restarted to apply a change or the Node it's on is being drained. Players are warned with a countdown, then kicked,
+// and the world is saved before the server stops. Not supported on Bedrock servers, which can't be warned over RCON.
+type ShutdownSpec struct {
+	// CountdownSeconds is how long players are warned for before the server stops. The countdown is skipped if nobody
+	// is online. Defaults to 30 seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CountdownSeconds *int32 `json:"countdownSeconds,omitempty"`
+	// Message is shown to players during the countdown and when they're kicked. Defaults to "Server is restarting".
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StopTimeoutSeconds is how long the server is given to save the world and stop after the countdown, before it's
+	// killed. Defaults to 60 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StopTimeoutSeconds *int32 `json:"stopTimeoutSeconds,omitempty"`
 }
 
 // +kubebuilder:validation:Enum=None;ClusterIP;NodePort;LoadBalancer
@@ -112,7 +565,9 @@ const ServiceTypeLoadBalancer ServiceType = "LoadBalancer"
 
 // ServiceSpec is very much like a corev1.ServiceSpec, but with only *some* fields.
 type ServiceSpec struct {
-	Type ServiceType `json:"type"`
+	// Type of Service to create. Defaults to ClusterIP.
+	// +optional
+	Type ServiceType `json:"type,omitempty"`
 	// Port to bind Minecraft to if using a NodePort or LoadBalancer service
 	MinecraftNodePort *int32 `json:"minecraftNodePort,omitempty"`
 }
@@ -124,9 +579,59 @@ const StatePending State = "Pending"
 const StateRunning State = "Running"
 const StateError State = "Error"
 
+// Condition types reported in MinecraftServerStatus.Conditions
+const (
+	// ConditionConfigReady indicates that the ConfigMaps holding the server's configuration are up-to-date.
+	ConditionConfigReady = "ConfigReady"
+	// ConditionServiceReady indicates that the Services exposing the server are up-to-date.
+	ConditionServiceReady = "ServiceReady"
+	// ConditionWorkloadReady indicates that the server's Pod is up-to-date and ready.
+	ConditionWorkloadReady = "WorkloadReady"
+	// ConditionEULAAccepted indicates that the Minecraft EULA has been accepted in the spec. The server will not start
+	// without it.
+	ConditionEULAAccepted = "EULAAccepted"
+	// ConditionResponding indicates that the operator was able to ping the server over the network. This is only
+	// reported if the operator is configured to check on servers itself.
+	ConditionResponding = "Responding"
+	// ConditionAvailable indicates that the server is fully reconciled and ready for players.
+	ConditionAvailable = "Available"
+)
+
 // MinecraftServerStatus defines the observed state of MinecraftServer
 type MinecraftServerStatus struct {
-	State State `json:"state"`
+	State State `json:"state,omitempty"`
+	// ObservedGeneration is the most recent generation of the MinecraftServer observed by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// ServerBuild is the build of the server software that was resolved for the Minecraft version, e.g., the Paper
+	// build number or the Forge version.
+	ServerBuild string `json:"serverBuild,omitempty"`
+	// PodName is the name of the Pod currently running the server, if any.
+	PodName string `json:"podName,omitempty"`
+	// NodeName is the name of the Node the server's Pod is scheduled to, if any.
+	NodeName string `json:"nodeName,omitempty"`
+	// Message is a human-readable explanation of the current state, typically the last error encountered.
+	Message string `json:"message,omitempty"`
+	// Players is who is currently online, as reported by the server itself. This is only set if the operator is able
+	// to talk to the server, and is refreshed periodically.
+	Players *PlayersStatus `json:"players,omitempty"`
+	// ReportedVersion is the version the server itself reports, e.g., "Paper 1.19.2". This may be different to the
+	// Minecraft version in the spec if the server is still starting up or has been modified.
+	ReportedVersion string `json:"reportedVersion,omitempty"`
+	// MOTD is the server's message of the day, as shown in the multiplayer menu, with any formatting removed.
+	MOTD string `json:"motd,omitempty"`
+}
+
+type PlayersStatus struct {
+	// Online is the number of players currently online.
+	Online int32 `json:"online"`
+	// Max is the maximum number of players allowed online at once.
+	Max int32 `json:"max"`
+	// Names of the players that are online.
+	// +listType=set
+	Names []string `json:"names,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -134,6 +639,15 @@ type MinecraftServerStatus struct {
 
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
 // +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.minecraftVersion`
+// +kubebuilder:printcolumn:name="Build",type=string,JSONPath=`.status.serverBuild`,priority=1
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`
+// +kubebuilder:printcolumn:name="Players",type=integer,JSONPath=`.status.players.online`
+// +kubebuilder:printcolumn:name="Max Players",type=integer,JSONPath=`.status.players.max`,priority=1
+// +kubebuilder:printcolumn:name="Pod",type=string,JSONPath=`.status.podName`,priority=1
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.status.nodeName`,priority=1
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // MinecraftServer is the Schema for the minecraftservers API
 type MinecraftServer struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,474 @@
+package v1alpha1
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/jvmflags"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/neoforge"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/propertiesfile"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/purpur"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/version"
+)
+
+// reservedServerProperties are the server.properties keys that the operator sets itself and relies on, so they can't
+// be set in spec.serverProperties. The value explains why.
+var reservedServerProperties = map[string]string{
+	"enable-rcon":       "RCON is always enabled, the operator uses it to manage the server",
+	"rcon.password":     "the RCON password is generated by the operator",
+	"rcon.port":         "the operator expects RCON on the default port",
+	"server-port":       "the operator expects the server on the default port, use spec.service to expose it",
+	"level-name":        "the world volumes are mounted assuming the default level name",
+	"white-list":        "use spec.accessMode instead",
+	"enforce-whitelist": "use spec.accessMode instead",
+}
+
+// reservedBedrockServerProperties are like reservedServerProperties, but for Bedrock servers.
+var reservedBedrockServerProperties = map[string]string{
+	"server-port":   "the operator expects the server on the default port, use spec.service to expose it",
+	"server-portv6": "the operator expects the server on the default port, use spec.service to expose it",
+	"level-name":    "the world volume is mounted assuming the default level name",
+	"allow-list":    "use spec.accessMode instead",
+}
+
+// playerXUID matches a player's Xbox Live user ID, which is a decimal number.
+var playerXUID = regexp.MustCompile(`^[0-9]+$`)
+
+// playerUUID matches a player's UUID in the hyphenated form that Minecraft uses in the allow and ops lists.
+var playerUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func (s *MinecraftServer) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(s).
+		Complete()
+}
+
+// DefaultedFieldsAnnotation is set on a MinecraftServer by the defaulting webhook to record which fields it filled in,
+// as a comma separated list of field paths. This makes it clear which values were chosen by the operator rather than
+// the user, and so which could change in future versions.
+const DefaultedFieldsAnnotation = "minecraft.jameslaverack.com/defaulted-fields"
+
+const (
+	DefaultMaxPlayers   int32 = 20
+	DefaultViewDistance int32 = 10
+)
+
+//+kubebuilder:webhook:path=/mutate-minecraft-jameslaverack-com-v1alpha1-minecraftserver,mutating=true,failurePolicy=fail,sideEffects=None,groups=minecraft.jameslaverack.com,resources=minecraftservers,verbs=create;update,versions=v1alpha1,name=mminecraftserver.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &MinecraftServer{}
+
+// Default fills in any fields that weren't set, and records which ones it filled in with DefaultedFieldsAnnotation. The
+// operator also applies these defaults itself, so servers behave the same even if the webhook isn't in use.
+func (s *MinecraftServer) Default() {
+	var defaulted []string
+	if s.Spec.GameMode == "" {
+		s.Spec.GameMode = GameModeSurvival
+		defaulted = append(defaulted, "spec.gameMode")
+	}
+	if s.Spec.AccessMode == "" {
+		s.Spec.AccessMode = AccessModeAllowListOnly
+		defaulted = append(defaulted, "spec.accessMode")
+	}
+	if s.Spec.MaxPlayers == nil {
+		maxPlayers := DefaultMaxPlayers
+		s.Spec.MaxPlayers = &maxPlayers
+		defaulted = append(defaulted, "spec.maxPlayers")
+	}
+	if s.Spec.ViewDistance == nil {
+		viewDistance := DefaultViewDistance
+		s.Spec.ViewDistance = &viewDistance
+		defaulted = append(defaulted, "spec.viewDistance")
+	}
+	// No Service at all is a valid choice, so we only default the type if there is one
+	if s.Spec.Service != nil && s.Spec.Service.Type == "" {
+		s.Spec.Service.Type = ServiceTypeClusterIP
+		defaulted = append(defaulted, "spec.service.type")
+	}
+	// The default resources depend on the type, so if the type has changed since they were defaulted they're
+	// defaulted again. Resources that have been changed since they were defaulted are left alone.
+	if s.Spec.Resources != nil && s.wasDefaulted("spec.resources") && isDefaultResourcesForAnotherType(s.Spec.Type, *s.Spec.Resources) {
+		s.Spec.Resources = nil
+	}
+	if s.Spec.Resources == nil {
+		resources := DefaultResources(s.Spec.Type)
+		s.Spec.Resources = &resources
+		defaulted = append(defaulted, "spec.resources")
+	}
+
+	if len(defaulted) == 0 {
+		return
+	}
+	// Keep a record of anything defaulted previously too
+	if previous := s.Annotations[DefaultedFieldsAnnotation]; previous != "" {
+		defaulted = append(defaulted, strings.Split(previous, ",")...)
+	}
+	sort.Strings(defaulted)
+	unique := defaulted[:1]
+	for _, f := range defaulted[1:] {
+		if f != unique[len(unique)-1] {
+			unique = append(unique, f)
+		}
+	}
+	if s.Annotations == nil {
+		s.Annotations = make(map[string]string)
+	}
+	s.Annotations[DefaultedFieldsAnnotation] = strings.Join(unique, ",")
+}
+
+// wasDefaulted is true if DefaultedFieldsAnnotation records that the given field was filled in by Default.
+func (s *MinecraftServer) wasDefaulted(path string) bool {
+	for _, f := range strings.Split(s.Annotations[DefaultedFieldsAnnotation], ",") {
+		if f == path {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefaultResourcesForAnotherType is true if the resources are exactly what DefaultResources gives a different type of
+// server.
+func isDefaultResourcesForAnotherType(serverType ServerType, resources corev1.ResourceRequirements) bool {
+	if apiequality.Semantic.DeepEqual(resources, DefaultResources(serverType)) {
+		return false
+	}
+	for _, t := range serverTypes {
+		if apiequality.Semantic.DeepEqual(resources, DefaultResources(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultResources is the resources we give a server of the given type if none are set. There's deliberately no CPU
+// limit, as CPU throttling makes the server lag.
+func DefaultResources(serverType ServerType) corev1.ResourceRequirements {
+	switch {
+	case serverType.IsForgeBased():
+		// Modpacks need a lot more memory
+		return corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("12Gi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourceCPU:    resource.MustParse("2"),
+			},
+		}
+	default:
+		return corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("6Gi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("2.5Gi"),
+				corev1.ResourceCPU:    resource.MustParse("2"),
+			},
+		}
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-minecraft-jameslaverack-com-v1alpha1-minecraftserver,mutating=false,failurePolicy=fail,sideEffects=None,groups=minecraft.jameslaverack.com,resources=minecraftservers,verbs=create;update,versions=v1alpha1,name=vminecraftserver.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &MinecraftServer{}
+
+// ValidateCreate checks the parts of the spec that can't be expressed in the CRD schema. Without this, problems are
+// only discovered when the operator tries to reconcile the server.
+func (s *MinecraftServer) ValidateCreate() error {
+	return s.invalid(s.validateSpec())
+}
+
+// ValidateUpdate checks the new spec just like ValidateCreate, and additionally checks the change itself is allowed.
+func (s *MinecraftServer) ValidateUpdate(old runtime.Object) error {
+	errs := s.validateSpec()
+	if oldServer, ok := old.(*MinecraftServer); ok {
+		errs = append(errs, s.validateChange(oldServer)...)
+	}
+	return s.invalid(errs)
+}
+
+// ValidateDelete allows all deletes.
+func (s *MinecraftServer) ValidateDelete() error {
+	return nil
+}
+
+func (s *MinecraftServer) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("MinecraftServer").GroupKind(), s.Name, errs)
+}
+
+func (s *MinecraftServer) validateSpec() field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	switch s.Spec.Type {
+	case ServerTypeForge:
+		if s.Spec.Forge == nil {
+			errs = append(errs, field.Required(spec.Child("forge"), "must be set for Forge servers"))
+		}
+	default:
+		if s.Spec.Forge != nil {
+			errs = append(errs, field.Forbidden(spec.Child("forge"), "may only be set for Forge servers"))
+		}
+	}
+	if s.Spec.Bedrock != nil && s.Spec.Type != ServerTypeBedrock {
+		errs = append(errs, field.Forbidden(spec.Child("bedrock"), "may only be set for Bedrock servers"))
+	}
+	if s.Spec.Type == ServerTypeBedrock {
+		errs = append(errs, s.validateBedrock()...)
+	}
+	if s.Spec.NeoForge != nil && s.Spec.Type != ServerTypeNeoForge {
+		errs = append(errs, field.Forbidden(spec.Child("neoForge"), "may only be set for NeoForge servers"))
+	}
+	if s.Spec.Type == ServerTypeNeoForge && (s.Spec.NeoForge == nil || s.Spec.NeoForge.NeoForgeVersion == "") {
+		if _, err := neoforge.VersionPrefix(s.Spec.MinecraftVersion); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("minecraftVersion"), s.Spec.MinecraftVersion,
+				err.Error()+", so neoForge.neoForgeVersion must be set"))
+		}
+	}
+	if s.Spec.Paper != nil && s.Spec.Type != ServerTypePaper && s.Spec.Type != ServerTypeFolia {
+		errs = append(errs, field.Forbidden(spec.Child("paper"), "may only be set for Paper and Folia servers"))
+	}
+	if s.Spec.Purpur != nil {
+		if s.Spec.Type != ServerTypePurpur {
+			errs = append(errs, field.Forbidden(spec.Child("purpur"), "may only be set for Purpur servers"))
+		}
+		// This is exactly what the operator does to write purpur.yml, so anything that gets past this can be written.
+		if _, err := purpur.Config(s.Spec.Purpur.TypedSettings(), s.Spec.Purpur.Settings); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("purpur", "settings"), s.Spec.Purpur.Settings, err.Error()))
+		}
+	}
+	if s.Spec.Fabric != nil {
+		if s.Spec.Type != ServerTypeFabric {
+			errs = append(errs, field.Forbidden(spec.Child("fabric"), "may only be set for Fabric servers"))
+		}
+		for i, m := range s.Spec.Fabric.Mods {
+			if u, err := url.Parse(m.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+				errs = append(errs, field.Invalid(spec.Child("fabric", "mods").Index(i).Child("url"), m.URL,
+					"must be an http or https URL"))
+			}
+		}
+	}
+	if !s.Spec.Type.IsPaperBased() && s.Spec.Dynmap != nil && s.Spec.Dynmap.Enabled {
+		errs = append(errs, field.Forbidden(spec.Child("dynmap"),
+			"Dynmap is a plugin, and is not supported on "+string(s.Spec.Type)+" servers"))
+	}
+
+	if w := s.Spec.World; w != nil {
+		world := spec.Child("world")
+		if w.Overworld == nil {
+			errs = append(errs, field.Required(world.Child("overworld"), "must be set to persist the world"))
+		}
+		// Paper keeps the other dimensions separately, everything else keeps them inside the world directory.
+		if !s.Spec.Type.IsPaperBased() {
+			if w.Nether != nil {
+				errs = append(errs, field.Forbidden(world.Child("nether"),
+					string(s.Spec.Type)+" servers store the Nether with the overworld"))
+			}
+			if w.TheEnd != nil {
+				errs = append(errs, field.Forbidden(world.Child("theEnd"),
+					string(s.Spec.Type)+" servers store the End with the overworld"))
+			}
+		} else {
+			if w.Nether == nil {
+				errs = append(errs, field.Required(world.Child("nether"), "must be set to persist the world"))
+			}
+			if w.TheEnd == nil {
+				errs = append(errs, field.Required(world.Child("theEnd"), "must be set to persist the world"))
+			}
+		}
+	}
+
+	if svc := s.Spec.Service; svc != nil && svc.MinecraftNodePort != nil &&
+		svc.Type != ServiceTypeNodePort && svc.Type != ServiceTypeLoadBalancer {
+		errs = append(errs, field.Forbidden(spec.Child("service", "minecraftNodePort"),
+			"may only be set for NodePort or LoadBalancer services"))
+	}
+
+	if w := s.Spec.Workload; w != nil && w.Storage != nil {
+		storage := spec.Child("workload", "storage")
+		if w.Type != WorkloadTypeStatefulSet {
+			errs = append(errs, field.Forbidden(storage, "may only be set for the StatefulSet workload type"))
+		}
+		if w.Storage.World != nil && s.Spec.World != nil {
+			errs = append(errs, field.Forbidden(storage.Child("world"), "may not be set together with spec.world"))
+		}
+		if w.Storage.PluginData != nil && !s.Spec.Type.IsPaperBased() {
+			errs = append(errs, field.Forbidden(storage.Child("pluginData"), "may only be set for servers with plugins"))
+		}
+		if p := s.Spec.Persistence; p != nil {
+			if w.Storage.PluginData != nil && p.PluginData != nil {
+				errs = append(errs, field.Forbidden(storage.Child("pluginData"),
+					"may not be set together with spec.persistence.pluginData"))
+			}
+			if w.Storage.ServerState != nil && p.ServerState != nil {
+				errs = append(errs, field.Forbidden(storage.Child("serverState"),
+					"may not be set together with spec.persistence.serverState"))
+			}
+		}
+		if w.Storage.Dynmap != nil {
+			if s.Spec.Dynmap == nil || !s.Spec.Dynmap.Enabled {
+				errs = append(errs, field.Forbidden(storage.Child("dynmap"), "may only be set if Dynmap is enabled"))
+			} else if s.Spec.Dynmap.MapStorage != nil {
+				errs = append(errs, field.Forbidden(storage.Child("dynmap"),
+					"may not be set together with spec.dynmap.persistentVolumeClaim"))
+			}
+		}
+	}
+
+	if p := s.Spec.Persistence; p != nil && p.PluginData != nil && !s.Spec.Type.IsPaperBased() {
+		errs = append(errs, field.Forbidden(spec.Child("persistence", "pluginData"), "may only be set for servers with plugins"))
+	}
+
+	if jvm := s.Spec.JVM; jvm != nil {
+		if jvm.GarbageCollector != "" && jvm.Profile != "" && jvm.Profile != JVMProfileDefault {
+			errs = append(errs, field.Forbidden(spec.Child("jvm", "garbageCollector"),
+				"may only be set with the default profile, the "+string(jvm.Profile)+" profile chooses its own garbage collector"))
+		}
+		if jvm.JavaVersion != nil && jvm.Image == "" {
+			errs = append(errs, field.Forbidden(spec.Child("jvm", "javaVersion"), "may only be set together with spec.jvm.image"))
+		}
+		if _, err := jvmflags.Flags(jvmflags.Profile(jvm.Profile), s.JavaVersion(), 0); err != nil {
+			message := err.Error()
+			if jvm.Image != "" && jvm.JavaVersion == nil {
+				message += ", set spec.jvm.javaVersion to the version of Java in spec.jvm.image"
+			}
+			errs = append(errs, field.Invalid(spec.Child("jvm", "profile"), jvm.Profile, message))
+		}
+	}
+
+	errs = append(errs, validatePlayers(spec.Child("allowList"), s.Spec.AllowList)...)
+	errs = append(errs, validatePlayers(spec.Child("opsList"), s.Spec.OpsList)...)
+	errs = append(errs, validateServerProperties(spec.Child("serverProperties"), s.Spec.Type, s.Spec.ServerProperties)...)
+
+	return errs
+}
+
+// validateBedrock checks a Bedrock server doesn't use anything that only Java Edition supports.
+func (s *MinecraftServer) validateBedrock() field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	javaOnly := []struct {
+		name string
+		set  bool
+	}{
+		{"vanillaTweaks", s.Spec.VanillaTweaks != nil},
+		{"monitoring", s.Spec.Monitoring != nil},
+		{"jvm", s.Spec.JVM != nil},
+		{"hardcore", s.Spec.Hardcore != nil},
+		{"pvp", s.Spec.PVP != nil},
+		{"spawnProtection", s.Spec.SpawnProtection != nil},
+		{"levelType", s.Spec.LevelType != ""},
+		{"allowFlight", s.Spec.AllowFlight != nil},
+		// There's no RCON to warn players with, the server just saves and stops when it's sent SIGTERM
+		{"shutdown", s.Spec.Shutdown != nil},
+	}
+	for _, f := range javaOnly {
+		if f.set {
+			errs = append(errs, field.Forbidden(spec.Child(f.name), "is not supported on Bedrock servers"))
+		}
+	}
+
+	// Bedrock's permissions.json only identifies players by XUID
+	for i, p := range s.Spec.OpsList {
+		if p.XUID == "" {
+			errs = append(errs, field.Required(spec.Child("opsList").Index(i).Child("xuid"),
+				"Bedrock servers identify operators by XUID"))
+		}
+	}
+	return errs
+}
+
+func validatePlayers(path *field.Path, players []Player) field.ErrorList {
+	var errs field.ErrorList
+	for i, p := range players {
+		if p.Name == "" && p.UUID == "" && p.XUID == "" {
+			errs = append(errs, field.Required(path.Index(i), "one of name, uuid, or xuid must be set"))
+		}
+		if p.XUID != "" && !playerXUID.MatchString(p.XUID) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("xuid"), p.XUID, "must be a number"))
+		}
+		if p.UUID != "" && !playerUUID.MatchString(p.UUID) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("uuid"), p.UUID,
+				"must be a UUID in the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"))
+		}
+	}
+	return errs
+}
+
+func validateServerProperties(path *field.Path, serverType ServerType, props map[string]string) field.ErrorList {
+	reserved, validate := reservedServerProperties, propertiesfile.ValidateServerProperty
+	if serverType == ServerTypeBedrock {
+		reserved, validate = reservedBedrockServerProperties, propertiesfile.ValidateBedrockServerProperty
+	}
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs field.ErrorList
+	for _, k := range keys {
+		v := props[k]
+		if reason, ok := reserved[k]; ok {
+			errs = append(errs, field.Forbidden(path.Key(k), "may not be set, "+reason))
+			continue
+		}
+		if err := validate(k, v); err != nil {
+			errs = append(errs, field.Invalid(path.Key(k), v, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateChange checks that the change from the old server is allowed.
+func (s *MinecraftServer) validateChange(old *MinecraftServer) field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	// Minecraft can upgrade a world to a newer version, but not back again. We can only check release versions, so
+	// let anything else through.
+	if c, err := version.Compare(s.Spec.MinecraftVersion, old.Spec.MinecraftVersion); err == nil && c < 0 {
+		errs = append(errs, field.Invalid(spec.Child("minecraftVersion"), s.Spec.MinecraftVersion,
+			"may not be downgraded from "+old.Spec.MinecraftVersion+", as worlds cannot be downgraded"))
+	}
+
+	// Swapping the world storage out from under a running server would lose anything it hasn't saved yet, and it would
+	// then save over the new world.
+	if old.Status.PodName != "" {
+		if !worldStorageEqual(s.Spec.World, old.Spec.World) {
+			errs = append(errs, field.Forbidden(spec.Child("world"),
+				"world storage may not be changed while the server is running"))
+		}
+		if s.Spec.Type != old.Spec.Type {
+			errs = append(errs, field.Forbidden(spec.Child("type"),
+				"may not be changed while the server is running"))
+		}
+	}
+
+	return errs
+}
+
+// worldStorageEqual returns true if the two worlds use the same storage, ignoring other settings like the seed.
+func worldStorageEqual(a, b *WorldSpec) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return apiequality.Semantic.DeepEqual(a.Overworld, b.Overworld) &&
+		apiequality.Semantic.DeepEqual(a.Nether, b.Nether) &&
+		apiequality.Semantic.DeepEqual(a.TheEnd, b.TheEnd)
+}
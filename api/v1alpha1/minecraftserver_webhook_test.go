@@ -0,0 +1,553 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func validServer() *MinecraftServer {
+	return &MinecraftServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: MinecraftServerSpec{
+			EULA:             EULAAcceptanceAccepted,
+			MinecraftVersion: "1.19.2",
+			Type:             ServerTypePaper,
+			AccessMode:       AccessModeAllowListOnly,
+			AllowList:        []Player{{Name: "Alice"}, {UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5"}},
+			GameMode:         GameModeSurvival,
+			Service:          &ServiceSpec{Type: ServiceTypeClusterIP},
+		},
+	}
+}
+
+func claim(name string) *corev1.PersistentVolumeClaimVolumeSource {
+	return &corev1.PersistentVolumeClaimVolumeSource{ClaimName: name}
+}
+
+func TestMinecraftServerValidateCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *MinecraftServer)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			modify: func(s *MinecraftServer) {},
+			valid:  true,
+		},
+		{
+			name:   "forge without forge spec",
+			modify: func(s *MinecraftServer) { s.Spec.Type = ServerTypeForge },
+		},
+		{
+			name:   "forge spec on paper",
+			modify: func(s *MinecraftServer) { s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"} },
+		},
+		{
+			name: "dynmap on forge",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeForge
+				s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+				s.Spec.Dynmap = &DynmapSpec{Enabled: true}
+			},
+		},
+		{
+			name: "persistent world",
+			modify: func(s *MinecraftServer) {
+				s.Spec.World = &WorldSpec{Overworld: claim("overworld"), Nether: claim("nether"), TheEnd: claim("end")}
+			},
+			valid: true,
+		},
+		{
+			name: "vanilla",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeVanilla
+				s.Spec.World = &WorldSpec{Overworld: claim("overworld")}
+			},
+			valid: true,
+		},
+		{
+			name: "vanilla world with the end",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeVanilla
+				s.Spec.World = &WorldSpec{Overworld: claim("overworld"), TheEnd: claim("end")}
+			},
+		},
+		{
+			name: "dynmap on vanilla",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeVanilla
+				s.Spec.Dynmap = &DynmapSpec{Enabled: true}
+			},
+		},
+		{
+			name: "fabric",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeFabric
+				s.Spec.Fabric = &FabricSpec{Mods: []ModSpec{{URL: "https://example.com/fabric-api.jar"}}}
+			},
+			valid: true,
+		},
+		{
+			name: "neoforge without neoforge spec",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeNeoForge
+				s.Spec.MinecraftVersion = "1.20.4"
+			},
+			valid: true,
+		},
+		{
+			name: "neoforge on a year-based version",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeNeoForge
+				s.Spec.MinecraftVersion = "26.1"
+			},
+			valid: true,
+		},
+		{
+			name: "neoforge on snapshot",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeNeoForge
+				s.Spec.MinecraftVersion = "24w14a"
+			},
+		},
+		{
+			name: "neoforge on snapshot with version",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeNeoForge
+				s.Spec.MinecraftVersion = "24w14a"
+				s.Spec.NeoForge = &NeoForgeSpec{NeoForgeVersion: "0.24w14a.3-beta"}
+			},
+			valid: true,
+		},
+		{
+			name: "neoforge spec on forge",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeForge
+				s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+				s.Spec.NeoForge = &NeoForgeSpec{NeoForgeVersion: "20.4.237"}
+			},
+		},
+		{
+			name: "bedrock",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeBedrock
+				s.Spec.MinecraftVersion = "1.21.2.02"
+				s.Spec.AllowList = []Player{{Name: "Alice", XUID: "2535416409672402"}}
+				s.Spec.OpsList = []Player{{Name: "Alice", XUID: "2535416409672402"}}
+				s.Spec.ServerProperties = map[string]string{"allow-cheats": "true"}
+			},
+			valid: true,
+		},
+		{
+			name: "bedrock op without xuid",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeBedrock
+				s.Spec.OpsList = []Player{{Name: "Alice"}}
+			},
+		},
+		{
+			name: "bedrock with java edition property",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeBedrock
+				s.Spec.ServerProperties = map[string]string{"motd": "Hello"}
+			},
+		},
+		{
+			name: "bedrock with vanilla tweaks",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeBedrock
+				s.Spec.VanillaTweaks = &VanillaTweaks{}
+			},
+		},
+		{
+			name: "bedrock with shutdown",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeBedrock
+				s.Spec.Shutdown = &ShutdownSpec{CountdownSeconds: pointer.Int32(10)}
+			},
+		},
+		{
+			name:   "bedrock spec on paper",
+			modify: func(s *MinecraftServer) { s.Spec.Bedrock = &BedrockSpec{Image: "ubuntu"} },
+		},
+		{
+			name:   "invalid xuid",
+			modify: func(s *MinecraftServer) { s.Spec.AllowList = []Player{{XUID: "alice"}} },
+		},
+		{
+			name: "purpur",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypePurpur
+				s.Spec.Purpur = &PurpurSpec{UseBetterMending: pointer.Bool(true)}
+			},
+			valid: true,
+		},
+		{
+			name: "folia on the default channel",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeFolia
+				s.Spec.Paper = &PaperSpec{Channel: PaperChannelDefault}
+			},
+			valid: true,
+		},
+		{
+			name: "paper spec on purpur",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypePurpur
+				s.Spec.Paper = &PaperSpec{Channel: PaperChannelExperimental}
+			},
+		},
+		{
+			name: "purpur setting isn't YAML",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypePurpur
+				s.Spec.Purpur = &PurpurSpec{Settings: map[string]string{"settings.motd": "[unclosed"}}
+			},
+		},
+		{
+			name: "purpur setting inside a typed setting",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypePurpur
+				s.Spec.Purpur = &PurpurSpec{
+					UseAlternateKeepalive: pointer.Bool(true),
+					Settings:              map[string]string{"settings.use-alternate-keepalive.enabled": "true"},
+				}
+			},
+		},
+		{
+			name:   "purpur spec on paper",
+			modify: func(s *MinecraftServer) { s.Spec.Purpur = &PurpurSpec{UseBetterMending: pointer.Bool(true)} },
+		},
+		{
+			name:   "fabric spec on paper",
+			modify: func(s *MinecraftServer) { s.Spec.Fabric = &FabricSpec{LoaderVersion: "0.15.6"} },
+		},
+		{
+			name: "fabric mod without URL",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeFabric
+				s.Spec.Fabric = &FabricSpec{Mods: []ModSpec{{SHA256Sum: "abc"}}}
+			},
+		},
+		{
+			name:   "world without overworld",
+			modify: func(s *MinecraftServer) { s.Spec.World = &WorldSpec{Seed: "1234"} },
+		},
+		{
+			name: "forge world with nether",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeForge
+				s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+				s.Spec.World = &WorldSpec{Overworld: claim("overworld"), Nether: claim("nether")}
+			},
+		},
+		{
+			name: "node port on cluster IP service",
+			modify: func(s *MinecraftServer) {
+				port := int32(30565)
+				s.Spec.Service.MinecraftNodePort = &port
+			},
+		},
+		{
+			name:   "player without name or UUID",
+			modify: func(s *MinecraftServer) { s.Spec.OpsList = []Player{{}} },
+		},
+		{
+			name:   "server properties",
+			modify: func(s *MinecraftServer) { s.Spec.ServerProperties = map[string]string{"max-tick-time": "-1"} },
+			valid:  true,
+		},
+		{
+			name:   "unknown server property",
+			modify: func(s *MinecraftServer) { s.Spec.ServerProperties = map[string]string{"view-distnace": "12"} },
+		},
+		{
+			name:   "reserved server property",
+			modify: func(s *MinecraftServer) { s.Spec.ServerProperties = map[string]string{"rcon.port": "25576"} },
+		},
+		{
+			name:   "mistyped server property",
+			modify: func(s *MinecraftServer) { s.Spec.ServerProperties = map[string]string{"pvp": "yes"} },
+		},
+		{
+			name: "garbage collector with profile",
+			modify: func(s *MinecraftServer) {
+				s.Spec.JVM = &JVMSpec{Profile: JVMProfileAikar, GarbageCollector: GarbageCollectorZGC}
+			},
+		},
+		{
+			name: "profile not supported by java version",
+			modify: func(s *MinecraftServer) {
+				s.Spec.MinecraftVersion = "1.16.5"
+				s.Spec.JVM = &JVMSpec{Profile: JVMProfileShenandoah}
+			},
+		},
+		{
+			name: "profile supported by java version of image",
+			modify: func(s *MinecraftServer) {
+				s.Spec.MinecraftVersion = "1.16.5"
+				s.Spec.JVM = &JVMSpec{Profile: JVMProfileZGCGenerational, Image: "example.com/java:21", JavaVersion: pointer.Int32(21)}
+			},
+			valid: true,
+		},
+		{
+			name: "image without java version",
+			modify: func(s *MinecraftServer) {
+				s.Spec.MinecraftVersion = "1.16.5"
+				s.Spec.JVM = &JVMSpec{Profile: JVMProfileZGCGenerational, Image: "example.com/java:21"}
+			},
+		},
+		{
+			name: "java version without image",
+			modify: func(s *MinecraftServer) {
+				s.Spec.MinecraftVersion = "1.16.5"
+				s.Spec.JVM = &JVMSpec{Profile: JVMProfileZGCGenerational, JavaVersion: pointer.Int32(21)}
+			},
+		},
+		{
+			name: "stateful set storage",
+			modify: func(s *MinecraftServer) {
+				size := resource.MustParse("10Gi")
+				s.Spec.Workload = &WorkloadSpec{
+					Type:    WorkloadTypeStatefulSet,
+					Storage: &StorageSpec{World: &size, PluginData: &size},
+				}
+			},
+			valid: true,
+		},
+		{
+			name: "storage on replica set",
+			modify: func(s *MinecraftServer) {
+				size := resource.MustParse("10Gi")
+				s.Spec.Workload = &WorkloadSpec{Storage: &StorageSpec{World: &size}}
+			},
+		},
+		{
+			name: "storage and existing world",
+			modify: func(s *MinecraftServer) {
+				size := resource.MustParse("10Gi")
+				s.Spec.World = &WorldSpec{Overworld: claim("overworld"), Nether: claim("nether"), TheEnd: claim("end")}
+				s.Spec.Workload = &WorkloadSpec{Type: WorkloadTypeStatefulSet, Storage: &StorageSpec{World: &size}}
+			},
+		},
+		{
+			name: "dynmap storage without dynmap",
+			modify: func(s *MinecraftServer) {
+				size := resource.MustParse("10Gi")
+				s.Spec.Workload = &WorkloadSpec{Type: WorkloadTypeStatefulSet, Storage: &StorageSpec{Dynmap: &size}}
+			},
+		},
+		{
+			name: "plugin data on forge",
+			modify: func(s *MinecraftServer) {
+				s.Spec.Type = ServerTypeForge
+				s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+				s.Spec.Persistence = &PersistenceSpec{PluginData: claim("plugins")}
+			},
+		},
+		{
+			name: "server state claim and storage",
+			modify: func(s *MinecraftServer) {
+				size := resource.MustParse("1Gi")
+				s.Spec.Persistence = &PersistenceSpec{ServerState: claim("state")}
+				s.Spec.Workload = &WorkloadSpec{Type: WorkloadTypeStatefulSet, Storage: &StorageSpec{ServerState: &size}}
+			},
+		},
+		{
+			name:   "malformed UUID",
+			modify: func(s *MinecraftServer) { s.Spec.AllowList = []Player{{UUID: "069a79f444e94726a5befca90e38aaf5"}} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validServer()
+			tt.modify(s)
+			err := s.ValidateCreate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMinecraftServerValidateUpdate(t *testing.T) {
+	running := func() *MinecraftServer {
+		s := validServer()
+		s.Spec.World = &WorldSpec{Overworld: claim("overworld"), Nether: claim("nether"), TheEnd: claim("end")}
+		s.Status.PodName = "test-abcde"
+		return s
+	}
+
+	t.Run("upgrade", func(t *testing.T) {
+		s := running()
+		s.Spec.MinecraftVersion = "1.19.3"
+		assert.NoError(t, s.ValidateUpdate(running()))
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		s := running()
+		s.Spec.MinecraftVersion = "1.18.2"
+		assert.Error(t, s.ValidateUpdate(running()))
+	})
+
+	t.Run("change seed while running", func(t *testing.T) {
+		s := running()
+		s.Spec.World.Seed = "1234"
+		assert.NoError(t, s.ValidateUpdate(running()))
+	})
+
+	t.Run("swap world while running", func(t *testing.T) {
+		s := running()
+		s.Spec.World.Overworld = claim("other")
+		assert.Error(t, s.ValidateUpdate(running()))
+	})
+
+	t.Run("swap world while stopped", func(t *testing.T) {
+		old := running()
+		old.Status.PodName = ""
+		s := running()
+		s.Spec.World.Overworld = claim("other")
+		assert.NoError(t, s.ValidateUpdate(old))
+	})
+}
+
+func TestMinecraftBackupValidate(t *testing.T) {
+	backup := func() *MinecraftBackup {
+		return &MinecraftBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: MinecraftBackupSpec{
+				Server:            MinecraftServerLocator{Name: "test"},
+				BackupDestination: claim("backups"),
+			},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, backup().ValidateCreate())
+	})
+
+	t.Run("no destination", func(t *testing.T) {
+		b := backup()
+		b.Spec.BackupDestination = nil
+		assert.Error(t, b.ValidateCreate())
+	})
+
+	t.Run("spec is immutable", func(t *testing.T) {
+		b := backup()
+		b.Spec.Server.Name = "other"
+		assert.Error(t, b.ValidateUpdate(backup()))
+	})
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, AddToScheme(scheme))
+	validator := func(server *MinecraftServer) *minecraftBackupValidator {
+		return &minecraftBackupValidator{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(server).Build()}
+	}
+
+	t.Run("java server", func(t *testing.T) {
+		assert.NoError(t, validator(validServer()).ValidateCreate(context.Background(), backup()))
+	})
+
+	t.Run("bedrock server", func(t *testing.T) {
+		s := validServer()
+		s.Spec.Type = ServerTypeBedrock
+		assert.Error(t, validator(s).ValidateCreate(context.Background(), backup()))
+	})
+
+	t.Run("no destination with validator", func(t *testing.T) {
+		b := backup()
+		b.Spec.BackupDestination = nil
+		assert.Error(t, validator(validServer()).ValidateCreate(context.Background(), b))
+	})
+}
+
+func TestMinecraftServerDefault(t *testing.T) {
+	t.Run("minimal", func(t *testing.T) {
+		s := &MinecraftServer{
+			Spec: MinecraftServerSpec{
+				EULA:             EULAAcceptanceAccepted,
+				MinecraftVersion: "1.19.2",
+				Type:             ServerTypeForge,
+				Service:          &ServiceSpec{},
+			},
+		}
+		s.Default()
+		assert.Equal(t, GameModeSurvival, s.Spec.GameMode)
+		assert.Equal(t, AccessModeAllowListOnly, s.Spec.AccessMode)
+		assert.Equal(t, DefaultMaxPlayers, *s.Spec.MaxPlayers)
+		assert.Equal(t, DefaultViewDistance, *s.Spec.ViewDistance)
+		assert.Equal(t, ServiceTypeClusterIP, s.Spec.Service.Type)
+		assert.Equal(t, DefaultResources(ServerTypeForge), *s.Spec.Resources)
+		assert.Equal(t, "spec.accessMode,spec.gameMode,spec.maxPlayers,spec.resources,spec.service.type,spec.viewDistance",
+			s.Annotations[DefaultedFieldsAnnotation])
+	})
+
+	t.Run("no service", func(t *testing.T) {
+		s := validServer()
+		s.Spec.Service = nil
+		s.Default()
+		assert.Nil(t, s.Spec.Service)
+	})
+
+	t.Run("keeps record of earlier defaults", func(t *testing.T) {
+		s := validServer()
+		s.Default()
+		recorded := s.Annotations[DefaultedFieldsAnnotation]
+		s.Spec.ViewDistance = nil
+		s.Default()
+		assert.Equal(t, recorded, s.Annotations[DefaultedFieldsAnnotation])
+
+		s.Default()
+		assert.Equal(t, recorded, s.Annotations[DefaultedFieldsAnnotation])
+	})
+
+	t.Run("type changed after resources were defaulted", func(t *testing.T) {
+		s := validServer()
+		s.Default()
+		s.Spec.Type = ServerTypeForge
+		s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+		s.Default()
+		assert.Equal(t, DefaultResources(ServerTypeForge), *s.Spec.Resources)
+	})
+
+	t.Run("type changed after defaulted resources were edited", func(t *testing.T) {
+		s := validServer()
+		s.Default()
+		s.Spec.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("8Gi")
+		s.Spec.Type = ServerTypeForge
+		s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+		s.Default()
+		assert.Equal(t, resource.MustParse("8Gi"), s.Spec.Resources.Limits[corev1.ResourceMemory])
+	})
+
+	t.Run("type changed with resources set by the user", func(t *testing.T) {
+		s := validServer()
+		resources := DefaultResources(ServerTypePaper)
+		s.Spec.Resources = &resources
+		s.Default()
+		s.Spec.Type = ServerTypeForge
+		s.Spec.Forge = &ForgeSpec{ForgeVersion: "43.1.1"}
+		s.Default()
+		assert.Equal(t, DefaultResources(ServerTypePaper), *s.Spec.Resources)
+	})
+
+	t.Run("nothing to default", func(t *testing.T) {
+		s := validServer()
+		s.Default()
+		s.Annotations = nil
+		s.Default()
+		assert.Empty(t, s.Annotations)
+	})
+}
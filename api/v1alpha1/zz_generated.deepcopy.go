@@ -7,9 +7,41 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BedrockSpec) DeepCopyInto(out *BedrockSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BedrockSpec.
+func (in *BedrockSpec) DeepCopy() *BedrockSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BedrockSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerOverlay) DeepCopyInto(out *ContainerOverlay) {
+	*out = *in
+	in.Container.DeepCopyInto(&out.Container)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverlay.
+func (in *ContainerOverlay) DeepCopy() *ContainerOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynmapSpec) DeepCopyInto(out *DynmapSpec) {
 	*out = *in
@@ -30,6 +62,26 @@ func (in *DynmapSpec) DeepCopy() *DynmapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricSpec) DeepCopyInto(out *FabricSpec) {
+	*out = *in
+	if in.Mods != nil {
+		in, out := &in.Mods, &out.Mods
+		*out = make([]ModSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricSpec.
+func (in *FabricSpec) DeepCopy() *FabricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ForgeSpec) DeepCopyInto(out *ForgeSpec) {
 	*out = *in
@@ -45,6 +97,41 @@ func (in *ForgeSpec) DeepCopy() *ForgeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMSpec) DeepCopyInto(out *JVMSpec) {
+	*out = *in
+	if in.JavaVersion != nil {
+		in, out := &in.JavaVersion, &out.JavaVersion
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Heap != nil {
+		in, out := &in.Heap, &out.Heap
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HeapPercentage != nil {
+		in, out := &in.HeapPercentage, &out.HeapPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMSpec.
+func (in *JVMSpec) DeepCopy() *JVMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinecraftBackup) DeepCopyInto(out *MinecraftBackup) {
 	*out = *in
@@ -146,7 +233,7 @@ func (in *MinecraftServer) DeepCopyInto(out *MinecraftServer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftServer.
@@ -232,11 +319,6 @@ func (in *MinecraftServerSpec) DeepCopyInto(out *MinecraftServerSpec) {
 		*out = new(WorldSpec)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceSpec)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.VanillaTweaks != nil {
 		in, out := &in.VanillaTweaks, &out.VanillaTweaks
 		*out = new(VanillaTweaks)
@@ -257,6 +339,113 @@ func (in *MinecraftServerSpec) DeepCopyInto(out *MinecraftServerSpec) {
 		*out = new(ForgeSpec)
 		**out = **in
 	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(ShutdownSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxPlayers != nil {
+		in, out := &in.MaxPlayers, &out.MaxPlayers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ViewDistance != nil {
+		in, out := &in.ViewDistance, &out.ViewDistance
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVMSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hardcore != nil {
+		in, out := &in.Hardcore, &out.Hardcore
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PVP != nil {
+		in, out := &in.PVP, &out.PVP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SimulationDistance != nil {
+		in, out := &in.SimulationDistance, &out.SimulationDistance
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SpawnProtection != nil {
+		in, out := &in.SpawnProtection, &out.SpawnProtection
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowFlight != nil {
+		in, out := &in.AllowFlight, &out.AllowFlight
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OnlineMode != nil {
+		in, out := &in.OnlineMode, &out.OnlineMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServerProperties != nil {
+		in, out := &in.ServerProperties, &out.ServerProperties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(PodTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(WorkloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(PersistenceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fabric != nil {
+		in, out := &in.Fabric, &out.Fabric
+		*out = new(FabricSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Paper != nil {
+		in, out := &in.Paper, &out.Paper
+		*out = new(PaperSpec)
+		**out = **in
+	}
+	if in.Purpur != nil {
+		in, out := &in.Purpur, &out.Purpur
+		*out = new(PurpurSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NeoForge != nil {
+		in, out := &in.NeoForge, &out.NeoForge
+		*out = new(NeoForgeSpec)
+		**out = **in
+	}
+	if in.Bedrock != nil {
+		in, out := &in.Bedrock, &out.Bedrock
+		*out = new(BedrockSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftServerSpec.
@@ -272,6 +461,18 @@ func (in *MinecraftServerSpec) DeepCopy() *MinecraftServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinecraftServerStatus) DeepCopyInto(out *MinecraftServerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Players != nil {
+		in, out := &in.Players, &out.Players
+		*out = new(PlayersStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftServerStatus.
@@ -284,6 +485,21 @@ func (in *MinecraftServerStatus) DeepCopy() *MinecraftServerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModSpec) DeepCopyInto(out *ModSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModSpec.
+func (in *ModSpec) DeepCopy() *ModSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
 	*out = *in
@@ -299,6 +515,61 @@ func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeoForgeSpec) DeepCopyInto(out *NeoForgeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeoForgeSpec.
+func (in *NeoForgeSpec) DeepCopy() *NeoForgeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NeoForgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaperSpec) DeepCopyInto(out *PaperSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaperSpec.
+func (in *PaperSpec) DeepCopy() *PaperSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PaperSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceSpec) DeepCopyInto(out *PersistenceSpec) {
+	*out = *in
+	if in.PluginData != nil {
+		in, out := &in.PluginData, &out.PluginData
+		*out = new(v1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.ServerState != nil {
+		in, out := &in.ServerState, &out.ServerState
+		*out = new(v1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceSpec.
+func (in *PersistenceSpec) DeepCopy() *PersistenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Player) DeepCopyInto(out *Player) {
 	*out = *in
@@ -314,6 +585,181 @@ func (in *Player) DeepCopy() *Player {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlayersStatus) DeepCopyInto(out *PlayersStatus) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlayersStatus.
+func (in *PlayersStatus) DeepCopy() *PlayersStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlayersStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMetadata) DeepCopyInto(out *PodMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMetadata.
+func (in *PodMetadata) DeepCopy() *PodMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplate) DeepCopyInto(out *PodTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplate.
+func (in *PodTemplate) DeepCopy() *PodTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplatePodSpec) DeepCopyInto(out *PodTemplatePodSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]ContainerOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VolumeOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplatePodSpec.
+func (in *PodTemplatePodSpec) DeepCopy() *PodTemplatePodSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplatePodSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PurpurSpec) DeepCopyInto(out *PurpurSpec) {
+	*out = *in
+	if in.UseAlternateKeepalive != nil {
+		in, out := &in.UseAlternateKeepalive, &out.UseAlternateKeepalive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseBetterMending != nil {
+		in, out := &in.UseBetterMending, &out.UseBetterMending
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SilkTouchSpawners != nil {
+		in, out := &in.SilkTouchSpawners, &out.SilkTouchSpawners
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LobotomizeVillagers != nil {
+		in, out := &in.LobotomizeVillagers, &out.LobotomizeVillagers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PurpurSpec.
+func (in *PurpurSpec) DeepCopy() *PurpurSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PurpurSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
@@ -334,6 +780,71 @@ func (in *ServiceSpec) DeepCopy() *ServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShutdownSpec) DeepCopyInto(out *ShutdownSpec) {
+	*out = *in
+	if in.CountdownSeconds != nil {
+		in, out := &in.CountdownSeconds, &out.CountdownSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StopTimeoutSeconds != nil {
+		in, out := &in.StopTimeoutSeconds, &out.StopTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShutdownSpec.
+func (in *ShutdownSpec) DeepCopy() *ShutdownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShutdownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.World != nil {
+		in, out := &in.World, &out.World
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PluginData != nil {
+		in, out := &in.PluginData, &out.PluginData
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Dynmap != nil {
+		in, out := &in.Dynmap, &out.Dynmap
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ServerState != nil {
+		in, out := &in.ServerState, &out.ServerState
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VanillaTweaks) DeepCopyInto(out *VanillaTweaks) {
 	*out = *in
@@ -369,6 +880,42 @@ func (in *VanillaTweaksDatapack) DeepCopy() *VanillaTweaksDatapack {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeOverlay) DeepCopyInto(out *VolumeOverlay) {
+	*out = *in
+	in.Volume.DeepCopyInto(&out.Volume)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeOverlay.
+func (in *VolumeOverlay) DeepCopy() *VolumeOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
+	*out = *in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
+func (in *WorkloadSpec) DeepCopy() *WorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorldSpec) DeepCopyInto(out *WorldSpec) {
 	*out = *in
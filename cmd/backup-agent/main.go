@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"time"
 
-	rcon "github.com/katnegermis/pocketmine-rcon"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -16,8 +15,12 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
 )
 
+// rconTimeout is how long we wait for the server to respond to RCON. Saving a large world can take a while.
+const rconTimeout = time.Minute * 2
+
 func main() {
 	log, err := zap.NewProduction()
 	defer log.Sync()
@@ -26,6 +29,7 @@ func main() {
 	serverObjectNamespace := os.Getenv("SERVER_OBJECT_NAME")
 	backupName := os.Getenv("BACKUP_NAME")
 	rconAddress := os.Getenv("RCON_ADDRESS")
+	rconPassword := os.Getenv("RCON_PASSWORD")
 	backupSourceDIR := os.Getenv("BACKUP_SOURCE_DIR")
 	backupDestPath := os.Getenv("BACKUP_DEST_PATH")
 
@@ -66,16 +70,21 @@ func main() {
 
 	// TODO make sure to time out after the lease expires!
 
-	// TODO Use a real password
-	conn, err := rcon.NewConnection(rconAddress, "password")
+	if rconPassword == "" {
+		log.Panic("No RCON password provided")
+	}
+	conn, err := dialRCON(ctx, rconAddress, rconPassword)
 	if err != nil {
 		log.With(zap.Error(err), zap.String("rcon-address", rconAddress)).Panic("Failed to connect to rcon")
 	}
-	_, err = conn.SendCommand("save-off")
+	defer conn.Close()
+	_, err = sendCommand(ctx, conn, "save-off")
 	if err != nil {
 		log.With(zap.Error(err), zap.String("rcon-address", rconAddress)).Panic("Failed to send save-off")
 	}
-	_, err = conn.SendCommand("save-all")
+	// Flush makes the server finish writing every chunk to disk before it responds, otherwise we could end up backing up
+	// a half-written world.
+	_, err = sendCommand(ctx, conn, "save-all flush")
 	if err != nil {
 		log.With(zap.Error(err), zap.String("rcon-address", rconAddress)).Panic("Failed to send save-all")
 	}
@@ -120,7 +129,7 @@ func main() {
 		log.With(zap.Error(err), zap.String("backup-dest-path", backupDestPath)).Panic("Failed to create backup")
 	}
 
-	_, err = conn.SendCommand("save-on")
+	_, err = sendCommand(ctx, conn, "save-on")
 	if err != nil {
 		log.With(zap.Error(err), zap.String("rcon-address", rconAddress)).Panic("Failed to send save-on")
 	}
@@ -128,6 +137,18 @@ func main() {
 	// Done!
 }
 
+func dialRCON(ctx context.Context, address, password string) (*rcon.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, rconTimeout)
+	defer cancel()
+	return rcon.Dial(ctx, address, password)
+}
+
+func sendCommand(ctx context.Context, conn *rcon.Client, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, rconTimeout)
+	defer cancel()
+	return conn.Command(ctx, command)
+}
+
 func acquireLease(ctx context.Context, client *rest.RESTClient, serverObjectName, serverObjectNamespace, name string) error {
 	for {
 		result := client.Get().Resource("minecraftservers").Name(serverObjectName).Namespace(serverObjectNamespace).Do(ctx)
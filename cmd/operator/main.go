@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/go-logr/zapr"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -16,6 +18,8 @@ import (
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/controller/minecraftbackup"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/controller/minecraftserver"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
 )
 
 var (
@@ -33,6 +37,10 @@ func main() {
 	flag.String("health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.Bool("leader-elect", false, "Enable leader election for controller manager. "+
 		"Enabling this will ensure there is only one active controller manager.")
+	flag.Bool("enable-webhooks", true, "Enable the admission webhooks. These need a serving certificate, see "+
+		"webhook-cert-dir.")
+	flag.String("webhook-cert-dir", "", "Directory containing the webhook serving certificate, tls.crt and tls.key. "+
+		"Defaults to <temp-dir>/k8s-webhook-server/serving-certs.")
 	flag.Parse()
 	viper.BindPFlags(flag.CommandLine)
 
@@ -49,6 +57,7 @@ func main() {
 		Scheme:                 scheme,
 		MetricsBindAddress:     viper.GetString("metrics-bind-address"),
 		Port:                   9443,
+		CertDir:                viper.GetString("webhook-cert-dir"),
 		HealthProbeBindAddress: viper.GetString("health-probe-bind-address"),
 		LeaderElection:         viper.GetBool("leader-elect"),
 		LeaderElectionID:       "95b821c2.jameslaverack.com",
@@ -60,6 +69,11 @@ func main() {
 	if err = (&minecraftserver.MinecraftServerReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		Ping:   slp.Ping,
+		DialRCON: func(ctx context.Context, address, password string) (minecraftserver.RCONClient, error) {
+			return rcon.Dial(ctx, address, password)
+		},
+		Sources: minecraftserver.DefaultSources(),
 	}).SetupWithManager(mgr); err != nil {
 		log.With(zap.Error(err), zap.String("controller", "MinecraftServer")).Fatal("Failed to setup controller")
 	}
@@ -71,6 +85,15 @@ func main() {
 		log.With(zap.Error(err), zap.String("controller", "MinecraftBackup")).Fatal("Failed to setup controller")
 	}
 
+	if viper.GetBool("enable-webhooks") {
+		if err = (&minecraftv1alpha1.MinecraftServer{}).SetupWebhookWithManager(mgr); err != nil {
+			log.With(zap.Error(err), zap.String("webhook", "MinecraftServer")).Fatal("Failed to setup webhook")
+		}
+		if err = (&minecraftv1alpha1.MinecraftBackup{}).SetupWebhookWithManager(mgr); err != nil {
+			log.With(zap.Error(err), zap.String("webhook", "MinecraftBackup")).Fatal("Failed to setup webhook")
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		log.With(zap.Error(err)).Fatal("Failed to setup health check endpoint")
 	}
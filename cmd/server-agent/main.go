@@ -0,0 +1,92 @@
+// The server agent is a small helper that the operator ships into every Minecraft server Pod. The server images we use
+// are stock Java images, so this gives us a way to run our own code alongside the server without building images for
+// every Java version. An init container copies this binary into a shared volume, and the server container runs it from
+// there.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
+)
+
+const usage = `Usage: server-agent <command> [flags]
+
+Commands:
+  install <dir>  Copy this binary into the given directory
+  probe          Check that the Minecraft server is responding to Server List Pings
+  shutdown       Warn players, save the world, and stop the Minecraft server over RCON
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "install":
+		err = install(os.Args[2:])
+	case "probe":
+		err = probe(os.Args[2:])
+	case "shutdown":
+		err = shutdown(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func install(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("install takes exactly one argument, the directory to install to")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dest, err := os.OpenFile(filepath.Join(args[0], filepath.Base(self)), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+	_, err = io.Copy(dest, src)
+	return err
+}
+
+func probe(args []string) error {
+	flags := flag.NewFlagSet("probe", flag.ContinueOnError)
+	address := flags.String("address", "127.0.0.1:25565", "Address of the Minecraft server")
+	timeout := flags.Duration("timeout", 5*time.Second, "How long to wait for the server to respond")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	status, err := slp.Ping(ctx, *address)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d/%d players online, %s latency\n",
+		status.Version.Name, status.Players.Online, status.Players.Max, status.Latency)
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
+)
+
+// countdownAnnouncements are the points in the countdown, in time remaining, at which we remind players that the
+// server is stopping. We always announce at the start of the countdown too.
+var countdownAnnouncements = []time.Duration{
+	time.Minute,
+	30 * time.Second,
+	10 * time.Second,
+	5 * time.Second,
+	4 * time.Second,
+	3 * time.Second,
+	2 * time.Second,
+	time.Second,
+}
+
+// shutdown is run as a preStop hook on the Minecraft container. It warns any players online with a countdown, kicks
+// them, saves the world, and then stops the server. It waits for the server to actually stop before returning, as
+// Kubernetes will kill the container as soon as we're done.
+func shutdown(args []string) error {
+	flags := flag.NewFlagSet("shutdown", flag.ContinueOnError)
+	address := flags.String("address", "127.0.0.1:25565", "Address of the Minecraft server")
+	rconAddress := flags.String("rcon-address", "127.0.0.1:25575", "Address of the Minecraft server's RCON port")
+	countdown := flags.Duration("countdown", 30*time.Second, "How long to warn players for before stopping the server")
+	stopTimeout := flags.Duration("stop-timeout", time.Minute, "How long to wait for the server to save and stop")
+	message := flags.String("message", "Server is restarting", "Message to show players")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	password := os.Getenv("RCON_PASSWORD")
+	if password == "" {
+		return fmt.Errorf("no RCON password provided, set RCON_PASSWORD")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *countdown+*stopTimeout)
+	defer cancel()
+
+	client, err := rcon.Dial(ctx, *rconAddress, password)
+	if err != nil {
+		return fmt.Errorf("failed to connect to rcon: %w", err)
+	}
+	defer client.Close()
+
+	response, err := client.Command(ctx, "list")
+	if err != nil {
+		return fmt.Errorf("failed to list players: %w", err)
+	}
+	players, err := rcon.ParseListResponse(response)
+	if err != nil {
+		return err
+	}
+
+	// No point making everyone wait if nobody is around to see it
+	if len(players) > 0 && *countdown > 0 {
+		fmt.Printf("%d players online, starting %s countdown\n", len(players), *countdown)
+		if err := countDown(ctx, client, *countdown, *message); err != nil {
+			return err
+		}
+	}
+
+	// Players may have come or gone during the countdown, so check again
+	response, err = client.Command(ctx, "list")
+	if err != nil {
+		return fmt.Errorf("failed to list players: %w", err)
+	}
+	players, err = rcon.ParseListResponse(response)
+	if err != nil {
+		return err
+	}
+	for _, player := range players {
+		if _, err := client.Command(ctx, "kick "+player+" "+*message); err != nil {
+			return fmt.Errorf("failed to kick %s: %w", player, err)
+		}
+	}
+
+	fmt.Println("Saving world")
+	if _, err := client.Command(ctx, "save-all flush"); err != nil {
+		return fmt.Errorf("failed to save world: %w", err)
+	}
+
+	fmt.Println("Stopping server")
+	// The server can close the connection before it responds, so we don't trust the result. Instead, we wait to see
+	// the server stop.
+	_, _ = client.Command(ctx, "stop")
+	if err := waitForStop(ctx, *address); err != nil {
+		return err
+	}
+	fmt.Println("Server stopped")
+	return nil
+}
+
+// countDown broadcasts the message to players, along with how long is left, at the start of the countdown and at each
+// of the countdownAnnouncements. It returns once the countdown is over.
+func countDown(ctx context.Context, client *rcon.Client, countdown time.Duration, message string) error {
+	end := time.Now().Add(countdown)
+	announce := func(remaining time.Duration) error {
+		_, err := client.Command(ctx, "say "+message+", stopping in "+formatRemaining(remaining))
+		return err
+	}
+
+	if err := announce(countdown); err != nil {
+		return fmt.Errorf("failed to announce shutdown: %w", err)
+	}
+	for _, at := range append(countdownAnnouncements, 0) {
+		if at >= countdown {
+			continue
+		}
+		select {
+		case <-time.After(time.Until(end.Add(-at))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if at == 0 {
+			break
+		}
+		if err := announce(at); err != nil {
+			return fmt.Errorf("failed to announce shutdown: %w", err)
+		}
+	}
+	return nil
+}
+
+func formatRemaining(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds == 1 {
+		return "1 second"
+	}
+	return fmt.Sprintf("%d seconds", seconds)
+}
+
+// waitForStop waits until the server stops accepting connections.
+func waitForStop(ctx context.Context, address string) error {
+	var d net.Dialer
+	for {
+		conn, err := d.DialContext(ctx, "tcp", address)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("timed out waiting for server to stop: %w", ctx.Err())
+			}
+			return nil
+		}
+		_ = conn.Close()
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for server to stop: %w", ctx.Err())
+		}
+	}
+}
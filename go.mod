@@ -5,7 +5,6 @@ go 1.19
 require (
 	github.com/ghodss/yaml v1.0.0
 	github.com/go-logr/zapr v1.2.0
-	github.com/katnegermis/pocketmine-rcon v0.0.0-20171229130351-03454839a2aa
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.0
@@ -0,0 +1,63 @@
+// Package apiclient has what's common to the clients for the APIs that say where to download each type of server from.
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Client makes GET requests to an API.
+type Client struct {
+	// BaseURL is where the API is. Paths are appended to it, so it has no trailing slash.
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// New creates a client for the API at the given base URL, using the default HTTP client.
+func New(baseURL string) Client {
+	return Client{
+		BaseURL:    baseURL,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// GetJSON requests the given path and decodes the JSON response into the given value. The path is relative to the base
+// URL, unless it's an absolute URL.
+func (c *Client) GetJSON(ctx context.Context, path string, into interface{}) error {
+	return c.get(ctx, path, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(into)
+	})
+}
+
+// GetXML is the same as GetJSON, but for APIs that respond with XML.
+func (c *Client) GetXML(ctx context.Context, path string, into interface{}) error {
+	return c.get(ctx, path, func(r io.Reader) error {
+		return xml.NewDecoder(r).Decode(into)
+	})
+}
+
+func (c *Client) get(ctx context.Context, path string, decode func(io.Reader) error) error {
+	target := path
+	if u, err := url.Parse(path); err != nil || !u.IsAbs() {
+		target = c.BaseURL + path
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s from %s", resp.Status, req.URL)
+	}
+	return errors.Wrapf(decode(resp.Body), "failed to decode response from %s", req.URL)
+}
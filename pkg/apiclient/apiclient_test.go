@@ -0,0 +1,60 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/thing.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "thing"}`)
+	})
+	mux.HandleFunc("/api/thing.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<thing><name>thing</name></thing>`)
+	})
+	mux.HandleFunc("/elsewhere.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "elsewhere"}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	c := New(server.URL + "/api")
+
+	type thing struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var got thing
+		require.NoError(t, c.GetJSON(context.Background(), "/thing.json", &got))
+		assert.Equal(t, "thing", got.Name)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		var got thing
+		require.NoError(t, c.GetXML(context.Background(), "/thing.xml", &got))
+		assert.Equal(t, "thing", got.Name)
+	})
+
+	t.Run("absolute URL", func(t *testing.T) {
+		var got thing
+		require.NoError(t, c.GetJSON(context.Background(), server.URL+"/elsewhere.json", &got))
+		assert.Equal(t, "elsewhere", got.Name)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var got thing
+		assert.Error(t, c.GetJSON(context.Background(), "/missing.json", &got))
+	})
+
+	t.Run("not JSON", func(t *testing.T) {
+		var got thing
+		assert.Error(t, c.GetJSON(context.Background(), "/thing.xml", &got))
+	})
+}
@@ -1,59 +1,54 @@
+// Package bibliothek is a client for PaperMC's downloads API, which lists and serves the builds of each PaperMC
+// project.
 package bibliothek
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/apiclient"
 )
 
-type VersionResponse struct {
-	ProjectID   string `json:"project_id"`
-	ProjectName string `json:"project_name"`
-	Version     string `json:"version"`
-	Builds      []int  `json:"builds"`
-}
+// DefaultBaseURL is where PaperMC's downloads API is.
+const DefaultBaseURL = "https://api.papermc.io"
 
-func LatestBuildForVersion(version string) (int, error) {
-	r, err := http.Get("https://api.papermc.io/v2/projects/paper/versions/" + version)
-	if err != nil {
-		return -1, err
-	}
+// Project is a PaperMC project.
+type Project string
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return -1, err
-	}
+const (
+	ProjectPaper     Project = "paper"
+	ProjectFolia     Project = "folia"
+	ProjectVelocity  Project = "velocity"
+	ProjectWaterfall Project = "waterfall"
+)
 
-	var ver VersionResponse
-	err = json.Unmarshal(body, &ver)
-	if err != nil {
-		return -1, err
-	}
+// Channel is the release channel of a build.
+type Channel string
 
-	if len(ver.Builds) == 0 {
-		return -1, errors.New("no builds for version")
-	}
+const (
+	// ChannelDefault builds are considered stable.
+	ChannelDefault Channel = "default"
+	// ChannelExperimental builds are early builds, typically for a new version of Minecraft.
+	ChannelExperimental Channel = "experimental"
+)
 
-	latestBuild := ver.Builds[0]
-	for _, build := range ver.Builds {
-		if build > latestBuild {
-			latestBuild = build
-		}
-	}
-	return latestBuild, nil
+type BuildsResponse struct {
+	ProjectID   string  `json:"project_id"`
+	ProjectName string  `json:"project_name"`
+	Version     string  `json:"version"`
+	Builds      []Build `json:"builds"`
 }
 
-type DownloadsResponse struct {
-	ProjectID   string              `json:"project_id"`
-	ProjectName string              `json:"project_name"`
-	Version     string              `json:"version"`
-	Build       int                 `json:"build"`
-	Time        string              `json:"time"`
-	Channel     string              `json:"channel"`
-	Promoted    bool                `json:"promoted"`
-	Downloads   map[string]Download `json:"downloads"`
+// Build is a single build of a project.
+type Build struct {
+	Build     int                 `json:"build"`
+	Time      string              `json:"time"`
+	Channel   Channel             `json:"channel"`
+	Promoted  bool                `json:"promoted"`
+	Downloads map[string]Download `json:"downloads"`
 }
 
 type Download struct {
@@ -61,27 +56,65 @@ type Download struct {
 	Sha256 string `json:"sha256"`
 }
 
-func GetDownloadURLAndSHA256(version string, build int) (string, string, error) {
-	r, err := http.Get(fmt.Sprintf("https://api.papermc.io/v2/projects/paper/versions/%s/builds/%d", version, build))
-	if err != nil {
-		return "", "", err
+// Client finds builds of PaperMC projects.
+type Client struct {
+	apiclient.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{Client: apiclient.New(baseURL)}
+}
+
+// Builds lists the builds of a project for a version, e.g., "1.20.4" for Paper or "3.3.0-SNAPSHOT" for Velocity.
+func (c *Client) Builds(ctx context.Context, project Project, version string) ([]Build, error) {
+	var resp BuildsResponse
+	if err := c.GetJSON(ctx, versionPath(project, version)+"/builds", &resp); err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s builds for version %s", project, version)
 	}
+	return resp.Builds, nil
+}
 
-	body, err := ioutil.ReadAll(r.Body)
+// LatestBuild finds the newest build of a project for a version. Builds on the default channel are always considered,
+// and experimental builds are only considered if the channel is ChannelExperimental.
+func (c *Client) LatestBuild(ctx context.Context, project Project, version string, channel Channel) (Build, error) {
+	builds, err := c.Builds(ctx, project, version)
 	if err != nil {
-		return "", "", err
+		return Build{}, err
+	}
+	var latest *Build
+	for i, b := range builds {
+		if b.Channel != ChannelDefault && b.Channel != channel {
+			continue
+		}
+		if latest == nil || b.Build > latest.Build {
+			latest = &builds[i]
+		}
 	}
+	if latest == nil {
+		return Build{}, errors.Errorf("no %s builds of %s for version %s", channel, project, version)
+	}
+	return *latest, nil
+}
 
-	var down DownloadsResponse
-	err = json.Unmarshal(body, &down)
-	if err != nil {
-		return "", "", err
+// Build gets a specific build of a project.
+func (c *Client) Build(ctx context.Context, project Project, version string, build int) (Build, error) {
+	var b Build
+	if err := c.GetJSON(ctx, fmt.Sprintf("%s/builds/%d", versionPath(project, version), build), &b); err != nil {
+		return Build{}, errors.Wrapf(err, "failed to get %s build %d for version %s", project, build, version)
 	}
+	return b, nil
+}
 
-	appDownload, ok := down.Downloads["application"]
+// ApplicationDownload is the URL and SHA-256 checksum of a build's application JAR.
+func (c *Client) ApplicationDownload(project Project, version string, build Build) (string, string, error) {
+	d, ok := build.Downloads["application"]
 	if !ok {
-		return "", "", errors.New("unable to find application download for version")
+		return "", "", errors.Errorf("unable to find application download for %s build %d", project, build.Build)
 	}
+	return fmt.Sprintf("%s%s/builds/%d/downloads/%s", c.BaseURL, versionPath(project, version), build.Build, url.PathEscape(d.Name)),
+		d.Sha256, nil
+}
 
-	return fmt.Sprintf("https://api.papermc.io/v2/projects/paper/versions/%s/builds/%d/downloads/%s", version, build, appDownload.Name), appDownload.Sha256, nil
+func versionPath(project Project, version string) string {
+	return "/v2/projects/" + url.PathEscape(string(project)) + "/versions/" + url.PathEscape(version)
 }
@@ -0,0 +1,74 @@
+package bibliothek
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeAPI(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/projects/paper/versions/1.20.4/builds", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"project_id": "paper", "version": "1.20.4", "builds": [
+  {"build": 400, "channel": "default", "downloads": {"application": {"name": "paper-1.20.4-400.jar", "sha256": "aaa"}}},
+  {"build": 401, "channel": "default", "downloads": {"application": {"name": "paper-1.20.4-401.jar", "sha256": "bbb"}}},
+  {"build": 402, "channel": "experimental", "downloads": {"application": {"name": "paper-1.20.4-402.jar", "sha256": "ccc"}}}
+]}`)
+	})
+	mux.HandleFunc("/v2/projects/folia/versions/1.20.4/builds", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"project_id": "folia", "version": "1.20.4", "builds": [
+  {"build": 2, "channel": "experimental", "downloads": {"application": {"name": "folia-1.20.4-2.jar", "sha256": "ddd"}}}
+]}`)
+	})
+	mux.HandleFunc("/v2/projects/paper/versions/1.20.4/builds/400", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"build": 400, "channel": "default", "downloads": {"application": {"name": "paper-1.20.4-400.jar", "sha256": "aaa"}}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatestBuild(t *testing.T) {
+	c := NewClient(fakeAPI(t).URL)
+
+	t.Run("default channel", func(t *testing.T) {
+		b, err := c.LatestBuild(context.Background(), ProjectPaper, "1.20.4", ChannelDefault)
+		require.NoError(t, err)
+		assert.Equal(t, 401, b.Build)
+	})
+
+	t.Run("experimental channel", func(t *testing.T) {
+		b, err := c.LatestBuild(context.Background(), ProjectPaper, "1.20.4", ChannelExperimental)
+		require.NoError(t, err)
+		assert.Equal(t, 402, b.Build)
+	})
+
+	t.Run("no builds on channel", func(t *testing.T) {
+		_, err := c.LatestBuild(context.Background(), ProjectFolia, "1.20.4", ChannelDefault)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := c.LatestBuild(context.Background(), ProjectPaper, "1.0", ChannelDefault)
+		assert.Error(t, err)
+	})
+}
+
+func TestApplicationDownload(t *testing.T) {
+	c := NewClient(fakeAPI(t).URL)
+
+	b, err := c.Build(context.Background(), ProjectPaper, "1.20.4", 400)
+	require.NoError(t, err)
+	url, sha256, err := c.ApplicationDownload(ProjectPaper, "1.20.4", b)
+	require.NoError(t, err)
+	assert.Equal(t, c.BaseURL+"/v2/projects/paper/versions/1.20.4/builds/400/downloads/paper-1.20.4-400.jar", url)
+	assert.Equal(t, "aaa", sha256)
+
+	_, _, err = c.ApplicationDownload(ProjectPaper, "1.20.4", Build{Build: 1})
+	assert.Error(t, err)
+}
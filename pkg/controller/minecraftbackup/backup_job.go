@@ -28,6 +28,15 @@ func BackupPod(ctx context.Context, k8s client.Client, backup *minecraftv1alpha1
 		backup.Status.State = minecraftv1alpha1.BackupStateFailed
 		return true, k8s.Update(ctx, backup)
 	}
+	// The webhook stops these being created, but it might not be in use or the server's type might have changed since
+	if server.Spec.Type == minecraftv1alpha1.ServerTypeBedrock {
+		if backup.Status.State != minecraftv1alpha1.BackupStateFailed {
+			log.Info("Bedrock servers can't be backed up")
+			backup.Status.State = minecraftv1alpha1.BackupStateFailed
+			return true, k8s.Status().Update(ctx, backup)
+		}
+		return false, nil
+	}
 
 	expectedJob := jobForBackup(backup, &server)
 	var actualJob batchv1.Job
@@ -56,6 +65,41 @@ func BackupPod(ctx context.Context, k8s client.Client, backup *minecraftv1alpha1
 	return false, nil
 }
 
+// WaitForRCONPassword is true if the server's RCON password has been rotated but its Pod hasn't been replaced to pick up
+// the new one yet. The backup would fail to authenticate, so we hold off on starting it. Once the Job has been created
+// there's nothing more to wait for.
+func WaitForRCONPassword(ctx context.Context, k8s client.Client, backup *minecraftv1alpha1.MinecraftBackup) (bool, error) {
+	var job batchv1.Job
+	err := k8s.Get(ctx, client.ObjectKey{Name: backup.Name, Namespace: backup.Namespace}, &job)
+	if client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+	if err == nil {
+		return false, nil
+	}
+
+	// Anything missing here is for BackupPod to deal with
+	var server minecraftv1alpha1.MinecraftServer
+	err = k8s.Get(ctx, client.ObjectKey{Name: backup.Spec.Server.Name, Namespace: backup.Namespace}, &server)
+	if err != nil || server.Status.PodName == "" {
+		return false, client.IgnoreNotFound(err)
+	}
+	var pod corev1.Pod
+	if err := k8s.Get(ctx, client.ObjectKey{Name: server.Status.PodName, Namespace: server.Namespace}, &pod); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	var secret corev1.Secret
+	err = k8s.Get(ctx, client.ObjectKey{Name: minecraftserver.RCONSecretNameForServer(&server), Namespace: server.Namespace}, &secret)
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if !minecraftserver.PodHasCurrentRCONPassword(&pod, &secret) {
+		logutil.FromContextOrNew(ctx).Info("Waiting for the server to restart with its rotated RCON password")
+		return true, nil
+	}
+	return false, nil
+}
+
 func jobForBackup(backup *minecraftv1alpha1.MinecraftBackup, server *minecraftv1alpha1.MinecraftServer) *batchv1.Job {
 	const overworldMountName = "world-overworld"
 	const netherMountName = "world-nether"
@@ -95,6 +139,17 @@ func jobForBackup(backup *minecraftv1alpha1.MinecraftBackup, server *minecraftv1
 									Name:  "RCON_ADDRESS",
 									Value: rconService.Name + ":" + strconv.Itoa(int(rconService.Spec.Ports[0].Port)),
 								},
+								{
+									Name: "RCON_PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: minecraftserver.RCONSecretNameForServer(server),
+											},
+											Key: minecraftserver.RCONPasswordSecretKey,
+										},
+									},
+								},
 								{
 									Name:  "BACKUP_SOURCE_DIR",
 									Value: "/var/minecraft/",
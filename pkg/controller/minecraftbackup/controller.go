@@ -2,6 +2,7 @@ package minecraftbackup
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	batchv1 "k8s.io/api/batch/v1"
@@ -15,6 +16,9 @@ import (
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
 )
 
+// rconPasswordRetryInterval is how often we check if a server has restarted to pick up a rotated RCON password.
+const rconPasswordRetryInterval = time.Second * 10
+
 type MinecraftBackupReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
@@ -51,6 +55,15 @@ func (r *MinecraftBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
+	waiting, err := WaitForRCONPassword(ctx, r.Client, &backup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if waiting {
+		// We don't watch the server's Pod, so we need to come back and check on it
+		return ctrl.Result{RequeueAfter: rconPasswordRetryInterval}, nil
+	}
+
 	done, err = BackupPod(ctx, r.Client, &backup)
 	if err != nil {
 		return ctrl.Result{}, err
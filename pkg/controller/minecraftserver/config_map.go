@@ -2,6 +2,7 @@ package minecraftserver
 
 import (
 	"context"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -17,6 +18,8 @@ import (
 	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/propertiesfile"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/purpur"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/version"
 )
 
 func configMapNameForServer(server *minecraftv1alpha1.MinecraftServer) string {
@@ -59,80 +62,278 @@ func ConfigMap(ctx context.Context, k8s client.Client, server *minecraftv1alpha1
 		return true, k8s.Update(ctx, &actualConfigMap)
 	}
 
-	//if !reflect.DeepEqual(actualConfigMap.Data, data) {
-	//	log.Info("ConfigMap data incorrect, updating")
-	//	actualConfigMap.Data = data
-	//	return true, k8s.Update(ctx, &actualConfigMap)
-	//}
+	// The server only reads the config when it starts, but as the config hash is part of the pod template the change
+	// here will cause the server to be restarted with the new config once we get to reconciling the ReplicaSet.
+	if !reflect.DeepEqual(actualConfigMap.Data, data) {
+		log.Info("ConfigMap data incorrect, updating")
+		actualConfigMap.Data = data
+		return true, k8s.Update(ctx, &actualConfigMap)
+	}
 
 	log.Debug("ConfigMap OK")
 	return false, nil
 }
 
-func configMapData(server minecraftv1alpha1.MinecraftServer) (map[string]string, error) {
-	config := make(map[string]string)
-
-	props := make(map[string]string, 0)
-	props["enable-rcon"] = "true"
-	// TODO Use a real passsword
-	props["rcon.password"] = "password"
+// serverProperties works out the contents of server.properties. In order of increasing precedence, the typed fields in
+// the spec are applied, then the free-form spec.serverProperties, then the settings that the operator relies on to
+// manage the server. Anything not set at all is left for Minecraft to default.
+func serverProperties(server *minecraftv1alpha1.MinecraftServer) map[string]string {
+	props := make(map[string]string)
 	if server.Spec.MOTD != "" {
 		props["motd"] = server.Spec.MOTD
 	}
 	if server.Spec.GameMode != "" {
 		props["gamemode"] = strings.ToLower(string(server.Spec.GameMode))
 	}
-	if server.Spec.MaxPlayers > 0 {
-		props["max-players"] = strconv.Itoa(server.Spec.MaxPlayers)
+	if server.Spec.MaxPlayers != nil {
+		props["max-players"] = strconv.Itoa(int(*server.Spec.MaxPlayers))
+	}
+	if server.Spec.ViewDistance != nil {
+		props["view-distance"] = strconv.Itoa(int(*server.Spec.ViewDistance))
+	}
+	if server.Spec.World != nil && server.Spec.World.Seed != "" {
+		props["level-seed"] = server.Spec.World.Seed
+	}
+	if server.Spec.Difficulty != "" {
+		props["difficulty"] = strings.ToLower(string(server.Spec.Difficulty))
+	}
+	if server.Spec.Hardcore != nil {
+		props["hardcore"] = strconv.FormatBool(*server.Spec.Hardcore)
+	}
+	if server.Spec.PVP != nil {
+		props["pvp"] = strconv.FormatBool(*server.Spec.PVP)
 	}
+	if server.Spec.SimulationDistance != nil {
+		props["simulation-distance"] = strconv.Itoa(int(*server.Spec.SimulationDistance))
+	}
+	if server.Spec.SpawnProtection != nil {
+		props["spawn-protection"] = strconv.Itoa(int(*server.Spec.SpawnProtection))
+	}
+	if server.Spec.LevelType != "" {
+		props["level-type"] = levelTypeProperty(server.Spec.LevelType, server.Spec.MinecraftVersion)
+	}
+	if server.Spec.AllowFlight != nil {
+		props["allow-flight"] = strconv.FormatBool(*server.Spec.AllowFlight)
+	}
+	if server.Spec.OnlineMode != nil {
+		props["online-mode"] = strconv.FormatBool(*server.Spec.OnlineMode)
+	}
+
+	for k, v := range server.Spec.ServerProperties {
+		props[k] = v
+	}
+
+	// The RCON password is deliberately not set here, as we don't want it in a ConfigMap. It's appended to
+	// server.properties from the RCON Secret when the Pod starts instead.
+	props["enable-rcon"] = "true"
+	props["rcon.port"] = strconv.Itoa(rconPort)
+	props["server-port"] = strconv.Itoa(minecraftPort)
+	// The world volumes are mounted assuming the default name
+	props["level-name"] = "world"
 	if server.Spec.AccessMode == minecraftv1alpha1.AccessModeAllowListOnly {
 		props["enforce-whitelist"] = "true"
 		props["white-list"] = "true"
 	}
+	return props
+}
+
+// bedrockServerProperties works out the contents of server.properties for a Bedrock server, in the same way as
+// serverProperties. Bedrock uses different keys to Java Edition for many of the same settings.
+func bedrockServerProperties(server *minecraftv1alpha1.MinecraftServer) map[string]string {
+	props := make(map[string]string)
+	if server.Spec.MOTD != "" {
+		props["server-name"] = server.Spec.MOTD
+	}
+	if server.Spec.GameMode != "" {
+		props["gamemode"] = strings.ToLower(string(server.Spec.GameMode))
+	}
+	if server.Spec.MaxPlayers != nil {
+		props["max-players"] = strconv.Itoa(int(*server.Spec.MaxPlayers))
+	}
+	if server.Spec.ViewDistance != nil {
+		props["view-distance"] = strconv.Itoa(int(*server.Spec.ViewDistance))
+	}
+	if server.Spec.SimulationDistance != nil {
+		props["tick-distance"] = strconv.Itoa(int(*server.Spec.SimulationDistance))
+	}
 	if server.Spec.World != nil && server.Spec.World.Seed != "" {
 		props["level-seed"] = server.Spec.World.Seed
 	}
-	config["server.properties"] = propertiesfile.Write(props)
+	if server.Spec.Difficulty != "" {
+		props["difficulty"] = strings.ToLower(string(server.Spec.Difficulty))
+	}
+	if server.Spec.OnlineMode != nil {
+		props["online-mode"] = strconv.FormatBool(*server.Spec.OnlineMode)
+	}
 
-	// We always write a eula.txt file, but we *only* put "true" in it if the MinecraftServer object has had the EULA
-	// explicitly accepted.
-	if server.Spec.EULA == minecraftv1alpha1.EULAAcceptanceAccepted {
-		config["eula.txt"] = "eula=true"
-	} else {
-		config["eula.txt"] = "eula=false"
+	for k, v := range server.Spec.ServerProperties {
+		props[k] = v
 	}
 
-	if len(server.Spec.AllowList) > 0 {
-		// We can directly marshall the Player objects
-		d, err := json.Marshal(server.Spec.AllowList)
+	props["server-port"] = strconv.Itoa(bedrockPort)
+	props["server-portv6"] = strconv.Itoa(bedrockPortV6)
+	// The world volume is mounted assuming the default name
+	props["level-name"] = "world"
+	props["allow-list"] = strconv.FormatBool(server.Spec.AccessMode == minecraftv1alpha1.AccessModeAllowListOnly)
+	return props
+}
+
+// bedrockConfigMapData is the config files for a Bedrock server. Bedrock identifies players by XUID rather than UUID,
+// and has its own formats for the allow list and the ops list.
+func bedrockConfigMapData(server minecraftv1alpha1.MinecraftServer) (map[string]string, error) {
+	config := make(map[string]string)
+
+	config["server.properties"] = propertiesfile.Write(bedrockServerProperties(&server))
+
+	// As with Java Edition, we always write these so that removing the last player from either list takes effect
+	type allowListEntry struct {
+		Name               string `json:"name,omitempty"`
+		XUID               string `json:"xuid,omitempty"`
+		IgnoresPlayerLimit bool   `json:"ignoresPlayerLimit"`
+	}
+	allowList := make([]allowListEntry, len(server.Spec.AllowList))
+	for i, p := range server.Spec.AllowList {
+		allowList[i] = allowListEntry{Name: p.Name, XUID: p.XUID}
+	}
+	d, err := json.Marshal(allowList)
+	if err != nil {
+		return nil, err
+	}
+	config["allowlist.json"] = string(d)
+
+	type permission struct {
+		Permission string `json:"permission"`
+		XUID       string `json:"xuid"`
+	}
+	permissions := make([]permission, len(server.Spec.OpsList))
+	for i, o := range server.Spec.OpsList {
+		permissions[i] = permission{Permission: "operator", XUID: o.XUID}
+	}
+	d, err = json.Marshal(permissions)
+	if err != nil {
+		return nil, err
+	}
+	config["permissions.json"] = string(d)
+
+	return config, nil
+}
+
+// purpurConfig works out the contents of purpur.yml. As with server.properties, the free-form spec.purpur.settings
+// take precedence over the typed fields. Anything not set is left for Purpur to default.
+func purpurConfig(server *minecraftv1alpha1.MinecraftServer) (map[string]interface{}, error) {
+	var spec minecraftv1alpha1.PurpurSpec
+	if server.Spec.Purpur != nil {
+		spec = *server.Spec.Purpur
+	}
+	return purpur.Config(spec.TypedSettings(), spec.Settings)
+}
+
+// levelTypeProperty is the value for level-type in server.properties. Minecraft 1.19 switched to using namespaced IDs
+// for these.
+func levelTypeProperty(levelType minecraftv1alpha1.LevelType, minecraftVersion string) string {
+	namespaced := true
+	// Snapshots and other versions we can't compare are probably new
+	if c, err := version.Compare(minecraftVersion, "1.19"); err == nil && c < 0 {
+		namespaced = false
+	}
+	switch levelType {
+	case minecraftv1alpha1.LevelTypeFlat:
+		if namespaced {
+			return "minecraft:flat"
+		}
+		return "flat"
+	case minecraftv1alpha1.LevelTypeLargeBiomes:
+		if namespaced {
+			return "minecraft:large_biomes"
+		}
+		return "largeBiomes"
+	case minecraftv1alpha1.LevelTypeAmplified:
+		if namespaced {
+			return "minecraft:amplified"
+		}
+		return "amplified"
+	default:
+		if namespaced {
+			return "minecraft:normal"
+		}
+		return "default"
+	}
+}
+
+func configMapData(server minecraftv1alpha1.MinecraftServer) (map[string]string, error) {
+	if server.Spec.Type == minecraftv1alpha1.ServerTypeBedrock {
+		return bedrockConfigMapData(server)
+	}
+
+	config := make(map[string]string)
+
+	config["server.properties"] = propertiesfile.Write(serverProperties(&server))
+
+	// Forge's run.sh reads JVM arguments from user_jvm_args.txt in the working directory, as does NeoForge's. The
+	// copy-config init container runs after the installer, so this replaces the one it generates.
+	if server.Spec.Type.IsForgeBased() {
+		args, err := jvmArgs(&server)
 		if err != nil {
 			return nil, err
 		}
-		config["whitelist.json"] = string(d)
+		config["user_jvm_args.txt"] = strings.Join(args, "\n") + "\n"
 	}
 
-	if len(server.Spec.OpsList) > 0 {
-		type op struct {
-			UUID                string `json:"uuid,omitempty"`
-			Name                string `json:"name,omitempty"`
-			Level               int    `json:"level"`
-			BypassesPlayerLimit string `json:"bypassesPlayerLimit"`
-		}
-		ops := make([]op, len(server.Spec.OpsList))
-		for i, o := range server.Spec.OpsList {
-			ops[i] = op{
-				UUID:                o.UUID,
-				Name:                o.Name,
-				Level:               4,
-				BypassesPlayerLimit: "false",
-			}
+	// As with the allow list below, this is written even if spec.purpur is empty so removing a setting takes effect.
+	if server.Spec.Type == minecraftv1alpha1.ServerTypePurpur {
+		c, err := purpurConfig(&server)
+		if err != nil {
+			return nil, err
 		}
-		d, err := json.Marshal(ops)
+		d, err := yaml.Marshal(c)
 		if err != nil {
 			return nil, err
 		}
-		config["ops.json"] = string(d)
+		config["purpur.yml"] = string(d)
+	}
+
+	// We always write a eula.txt file, but we *only* put "true" in it if the MinecraftServer object has had the EULA
+	// explicitly accepted.
+	if server.Spec.EULA == minecraftv1alpha1.EULAAcceptanceAccepted {
+		config["eula.txt"] = "eula=true"
+	} else {
+		config["eula.txt"] = "eula=false"
+	}
+
+	// We always write the allow list and ops list, even if they're empty. The copy-config init container will only ever
+	// overwrite files in the server's working directory, so if we didn't then removing the last player from either list
+	// would leave the old file in place.
+	allowList := server.Spec.AllowList
+	if allowList == nil {
+		allowList = []minecraftv1alpha1.Player{}
+	}
+	// We can directly marshall the Player objects
+	d, err := json.Marshal(allowList)
+	if err != nil {
+		return nil, err
+	}
+	config["whitelist.json"] = string(d)
+
+	type op struct {
+		UUID                string `json:"uuid,omitempty"`
+		Name                string `json:"name,omitempty"`
+		Level               int    `json:"level"`
+		BypassesPlayerLimit string `json:"bypassesPlayerLimit"`
+	}
+	ops := make([]op, len(server.Spec.OpsList))
+	for i, o := range server.Spec.OpsList {
+		ops[i] = op{
+			UUID:                o.UUID,
+			Name:                o.Name,
+			Level:               4,
+			BypassesPlayerLimit: "false",
+		}
+	}
+	d, err = json.Marshal(ops)
+	if err != nil {
+		return nil, err
 	}
+	config["ops.json"] = string(d)
 
 	if server.Spec.Monitoring != nil && server.Spec.Monitoring.Type == minecraftv1alpha1.MonitoringTypePrometheusServiceMonitor {
 		// prometheus-exporter plugin file
@@ -0,0 +1,149 @@
+package minecraftserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func TestServerProperties(t *testing.T) {
+	t.Run("typed fields", func(t *testing.T) {
+		server := generateTestServer()
+		pvp := false
+		spawnProtection := int32(0)
+		server.Spec.PVP = &pvp
+		server.Spec.SpawnProtection = &spawnProtection
+		server.Spec.Difficulty = v1alpha1.DifficultyHard
+		server.Spec.LevelType = v1alpha1.LevelTypeAmplified
+
+		props := serverProperties(&server)
+		assert.Equal(t, "false", props["pvp"])
+		assert.Equal(t, "0", props["spawn-protection"])
+		assert.Equal(t, "hard", props["difficulty"])
+		assert.Equal(t, "minecraft:amplified", props["level-type"])
+		assert.NotContains(t, props, "hardcore")
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		server := generateTestServer()
+		server.Spec.AccessMode = v1alpha1.AccessModeAllowListOnly
+		server.Spec.MOTD = "From the spec"
+		server.Spec.ServerProperties = map[string]string{
+			"motd":          "From serverProperties",
+			"enable-rcon":   "false",
+			"white-list":    "false",
+			"max-tick-time": "-1",
+		}
+
+		props := serverProperties(&server)
+		assert.Equal(t, "From serverProperties", props["motd"])
+		assert.Equal(t, "-1", props["max-tick-time"])
+		// The operator's own settings always win
+		assert.Equal(t, "true", props["enable-rcon"])
+		assert.Equal(t, "true", props["white-list"])
+	})
+}
+
+func TestLevelTypeProperty(t *testing.T) {
+	assert.Equal(t, "minecraft:large_biomes", levelTypeProperty(v1alpha1.LevelTypeLargeBiomes, "1.19.2"))
+	assert.Equal(t, "largeBiomes", levelTypeProperty(v1alpha1.LevelTypeLargeBiomes, "1.18.2"))
+	assert.Equal(t, "default", levelTypeProperty(v1alpha1.LevelTypeNormal, "1.16.5"))
+	assert.Equal(t, "minecraft:flat", levelTypeProperty(v1alpha1.LevelTypeFlat, "22w45a"))
+}
+
+func TestForgeUserJVMArgs(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeForge
+	server.Spec.JVM = &v1alpha1.JVMSpec{
+		Profile:   v1alpha1.JVMProfileZGCGenerational,
+		ExtraArgs: []string{"-Dfml.queryResult=confirm"},
+	}
+
+	data, err := configMapData(server)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"-Xms9216M\n-Xmx9216M\n-XX:+UseZGC\n-XX:+AlwaysPreTouch\n-XX:+PerfDisableSharedMem\n-Dfml.queryResult=confirm\n",
+		data["user_jvm_args.txt"])
+
+	server.Spec.Type = v1alpha1.ServerTypePaper
+	data, err = configMapData(server)
+	require.NoError(t, err)
+	assert.NotContains(t, data, "user_jvm_args.txt")
+}
+
+func TestPurpurConfig(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypePurpur
+	server.Spec.Purpur = &v1alpha1.PurpurSpec{
+		UseBetterMending:    pointer.Bool(true),
+		LobotomizeVillagers: pointer.Bool(true),
+		Settings: map[string]string{
+			"world-settings.default.gameplay-mechanics.use-better-mending":               "false",
+			"world-settings.default.gameplay-mechanics.player.idle-timeout.kick-if-idle": "false",
+			"settings.blocks.barrel.rows":                                                "6",
+		},
+	}
+
+	data, err := configMapData(server)
+	require.NoError(t, err)
+	assert.YAMLEq(t, `
+settings:
+  blocks:
+    barrel:
+      rows: 6
+world-settings:
+  default:
+    gameplay-mechanics:
+      use-better-mending: false
+      player:
+        idle-timeout:
+          kick-if-idle: false
+    mobs:
+      villager:
+        lobotomize:
+          enabled: true
+`, data["purpur.yml"])
+
+	server.Spec.Purpur.Settings = map[string]string{"world-settings.default.mobs.villager": "true"}
+	_, err = configMapData(server)
+	assert.Error(t, err, "a setting can't replace a section that another setting is in")
+
+	server.Spec.Purpur = nil
+	data, err = configMapData(server)
+	require.NoError(t, err)
+	assert.Equal(t, "{}\n", data["purpur.yml"])
+}
+
+func TestBedrockConfigMapData(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeBedrock
+	server.Spec.MOTD = "Hello"
+	server.Spec.SimulationDistance = pointer.Int32(6)
+	server.Spec.AccessMode = v1alpha1.AccessModeAllowListOnly
+	server.Spec.AllowList = []v1alpha1.Player{{Name: "Alice", XUID: "2535416409672402"}, {Name: "Bob"}}
+	server.Spec.OpsList = []v1alpha1.Player{{Name: "Alice", XUID: "2535416409672402"}}
+	server.Spec.ServerProperties = map[string]string{"allow-cheats": "true"}
+
+	data, err := configMapData(server)
+	require.NoError(t, err)
+	assert.NotContains(t, data, "eula.txt")
+	assert.NotContains(t, data, "whitelist.json")
+
+	props := bedrockServerProperties(&server)
+	assert.Equal(t, "Hello", props["server-name"])
+	assert.Equal(t, "6", props["tick-distance"])
+	assert.Equal(t, "true", props["allow-cheats"])
+	assert.Equal(t, "true", props["allow-list"])
+	assert.Equal(t, "19132", props["server-port"])
+	assert.NotContains(t, props, "enable-rcon")
+	assert.NotContains(t, props, "motd")
+
+	assert.JSONEq(t,
+		`[{"name": "Alice", "xuid": "2535416409672402", "ignoresPlayerLimit": false}, {"name": "Bob", "ignoresPlayerLimit": false}]`,
+		data["allowlist.json"])
+	assert.JSONEq(t, `[{"permission": "operator", "xuid": "2535416409672402"}]`, data["permissions.json"])
+}
@@ -6,20 +6,34 @@ import (
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
 )
 
+// PingFunc performs a Server List Ping against the server at the given address. See slp.Ping.
+type PingFunc func(ctx context.Context, address string) (*slp.Status, error)
+
 type MinecraftServerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Ping, if set, is used to check that running servers are responding. This requires the operator to be able to
+	// connect to server Pods directly.
+	Ping PingFunc
+	// DialRCON, if set, is used to ask running servers who is online. It's only used if Ping is also set.
+	DialRCON RCONDialFunc
+	// Sources are used to find the server software to download. See DefaultSources.
+	Sources *Sources
 }
 
-func (r *MinecraftServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *MinecraftServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := logutil.FromContextOrNew(ctx).With(
 		zap.String("name", req.Name),
 		zap.String("namespace", req.Namespace),
@@ -37,70 +51,106 @@ func (r *MinecraftServerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	if err := r.Get(ctx, req.NamespacedName, &server); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	// Apply the same defaults the webhook would have, in case it isn't in use. This is only in memory, we never write
+	// the spec back.
+	server.Default()
+
+	// Whatever happens below, we write what we found back to the status when we're done. Each step records its outcome
+	// in the conditions as we go.
+	originalStatus := server.Status.DeepCopy()
+	defer func() {
+		if statusErr := updateStatus(ctx, r.Client, &server, originalStatus); statusErr != nil {
+			log.With(zap.Error(statusErr)).Error("Failed to update status")
+			if err == nil {
+				err = statusErr
+			}
+		}
+	}()
+	server.Status.ObservedGeneration = server.Generation
+	setEULACondition(&server)
 
 	// We'll now create each resource we need. In general we'll "reconcile" each resource in turn. If there's work to be
 	// done we'll do it an exit instantly. This is because this function is triggered on changes to owned resources, so
 	// the act of creating or modifying an owned resource will cause this function to be called again anyway.
-
-	done, err := ConfigMap(ctx, r.Client, &server)
-	if err != nil {
-		return ctrl.Result{}, err
+	type step struct {
+		condition string
+		reconcile func(context.Context, client.Client, *minecraftv1alpha1.MinecraftServer) (bool, error)
 	}
-	if done {
-		return ctrl.Result{}, nil
+	steps := []step{
+		{minecraftv1alpha1.ConditionConfigReady, ConfigMap},
+	}
+	// Bedrock servers don't have RCON
+	bedrock := server.Spec.Type == minecraftv1alpha1.ServerTypeBedrock
+	if !bedrock {
+		steps = append(steps, step{minecraftv1alpha1.ConditionConfigReady, RCONSecret})
 	}
-
 	if server.Spec.Dynmap != nil && server.Spec.Dynmap.Enabled {
-		done, err := DynmapConfigMap(ctx, r.Client, &server)
+		steps = append(steps,
+			step{minecraftv1alpha1.ConditionConfigReady, DynmapConfigMap},
+			step{minecraftv1alpha1.ConditionServiceReady, DynmapService})
+	}
+	steps = append(steps, step{minecraftv1alpha1.ConditionServiceReady, Service})
+	if !bedrock {
+		steps = append(steps, step{minecraftv1alpha1.ConditionServiceReady, RCONService})
+	}
+
+	for i, s := range steps {
+		done, err := s.reconcile(ctx, r.Client, &server)
 		if err != nil {
+			markFailed(&server, s.condition, err)
 			return ctrl.Result{}, err
 		}
 		if done {
+			markUpdating(&server, s.condition, "Resources are being updated")
 			return ctrl.Result{}, nil
 		}
-		done, err = DynmapService(ctx, r.Client, &server)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-		if done {
-			return ctrl.Result{}, nil
+		// A condition is only true once every step that contributes to it has passed
+		if i == len(steps)-1 || steps[i+1].condition != s.condition {
+			setCondition(&server, s.condition, metav1.ConditionTrue, reasonReconciled, "")
 		}
 	}
 
-	done, err = Service(ctx, r.Client, &server)
+	done, err := Workload(ctx, r.Client, r.Sources, &server)
 	if err != nil {
+		markFailed(&server, minecraftv1alpha1.ConditionWorkloadReady, err)
 		return ctrl.Result{}, err
 	}
 	if done {
+		markUpdating(&server, minecraftv1alpha1.ConditionWorkloadReady, "Workload is being updated")
 		return ctrl.Result{}, nil
 	}
 
-	done, err = RCONService(ctx, r.Client, &server)
+	pod, err := observeWorkload(ctx, r.Client, &server)
 	if err != nil {
+		markFailed(&server, minecraftv1alpha1.ConditionWorkloadReady, err)
 		return ctrl.Result{}, err
 	}
-	if done {
-		return ctrl.Result{}, nil
-	}
 
-	done, err = ReplicaSet(ctx, r.Client, &server)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	if done {
+	// Bedrock servers don't answer Server List Pings, so there's nothing more we can find out about them
+	if r.Ping == nil || bedrock {
+		summariseStatus(&server)
+		log.Info("All good")
 		return ctrl.Result{}, nil
 	}
 
-	// All good, return
+	r.observeServer(ctx, &server, pod)
+	summariseStatus(&server)
+
+	// All good, but come back later to see who's online
 	log.Info("All good")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
 }
 
 func (r *MinecraftServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&minecraftv1alpha1.MinecraftServer{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.ReplicaSet{}).
+		Owns(&appsv1.StatefulSet{}).
+		// Pods are owned by the workload and not by us directly, so we map them back to the server by label instead.
+		// We need to see them to report on which Pod is running the server and if it's ready.
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(serverForPod)).
 		Complete(r)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -53,8 +55,9 @@ func setupTestingEnvironment(ctx context.Context, t *testing.T) (client.WithWatc
 	require.NoError(t, err)
 
 	minecraftServerReconciler := MinecraftServerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		Sources: DefaultSources(),
 	}
 	err = minecraftServerReconciler.SetupWithManager(mgr)
 
@@ -126,6 +129,15 @@ func TestBasicServer(t *testing.T) {
 	assertOwnerReference(t, &server, &configMap)
 	opsFile := configMap.Data["server.properties"]
 	assert.NotEmpty(t, opsFile)
+	assert.NotContains(t, opsFile, "rcon.password")
+
+	// RCON Secret
+	var secret corev1.Secret
+	require.Eventually(t, func() bool {
+		return k8s.Get(ctx, client.ObjectKey{Name: RCONSecretNameForServer(&server), Namespace: server.Namespace}, &secret) == nil
+	}, timeout, tick)
+	assertOwnerReference(t, &server, &secret)
+	assert.Len(t, secret.Data[RCONPasswordSecretKey], 64)
 
 	// ReplicaSet
 	var replicaSet appsv1.ReplicaSet
@@ -143,4 +155,45 @@ func TestBasicServer(t *testing.T) {
 	}, timeout, tick)
 
 	assertOwnerReference(t, &server, &service)
+
+	// Status. There's no controller manager running in the test environment, so no Pod will ever be created for the
+	// ReplicaSet. We should still report everything else as ready.
+	require.Eventually(t, func() bool {
+		err := k8s.Get(ctx, client.ObjectKeyFromObject(&server), &server)
+		return err == nil && meta.IsStatusConditionTrue(server.Status.Conditions, minecraftv1alpha1.ConditionServiceReady)
+	}, timeout, tick)
+	assert.Equal(t, server.Generation, server.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionTrue(server.Status.Conditions, minecraftv1alpha1.ConditionEULAAccepted))
+	assert.True(t, meta.IsStatusConditionTrue(server.Status.Conditions, minecraftv1alpha1.ConditionConfigReady))
+	assert.True(t, meta.IsStatusConditionFalse(server.Status.Conditions, minecraftv1alpha1.ConditionAvailable))
+	assert.Equal(t, minecraftv1alpha1.StatePending, server.Status.State)
+}
+
+func TestConfigMapUpdated(t *testing.T) {
+	ctx := context.Background()
+	k8s, teardown := setupTestingEnvironment(ctx, t)
+	defer teardown()
+
+	server := generateTestServer()
+	server.Spec.MOTD = "Before"
+	err := k8s.Create(ctx, &server)
+	require.NoError(t, err)
+
+	var configMap corev1.ConfigMap
+	require.Eventually(t, func() bool {
+		return k8s.Get(ctx, client.ObjectKeyFromObject(&server), &configMap) == nil
+	}, timeout, tick)
+	assert.Contains(t, configMap.Data["server.properties"], "motd=Before")
+
+	// Change the spec, and the ConfigMap should follow
+	require.NoError(t, k8s.Get(ctx, client.ObjectKeyFromObject(&server), &server))
+	server.Spec.MOTD = "After"
+	server.Spec.AllowList = []minecraftv1alpha1.Player{{Name: "Player1"}}
+	require.NoError(t, k8s.Update(ctx, &server))
+
+	require.Eventually(t, func() bool {
+		err := k8s.Get(ctx, client.ObjectKeyFromObject(&server), &configMap)
+		return err == nil && strings.Contains(configMap.Data["server.properties"], "motd=After")
+	}, timeout, tick)
+	assert.JSONEq(t, `[{"name":"Player1"}]`, configMap.Data["whitelist.json"])
 }
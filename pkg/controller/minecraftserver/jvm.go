@@ -0,0 +1,81 @@
+package minecraftserver
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/jvmflags"
+)
+
+// defaultHeapPercentage is how much of the container's memory we give to the Java heap by default. The rest is left
+// for the JVM's own use outside the heap, like thread stacks and the code cache.
+const defaultHeapPercentage = 75
+
+const mebibyte = 1024 * 1024
+
+// heapSizeMiB works out how big the Java heap should be, in MiB. Zero means there's nothing to size it from, so the JVM
+// should be left to choose for itself.
+func heapSizeMiB(server *minecraftv1alpha1.MinecraftServer) int64 {
+	jvm := server.Spec.JVM
+	if jvm != nil && jvm.Heap != nil {
+		return jvm.Heap.Value() / mebibyte
+	}
+
+	percentage := int64(defaultHeapPercentage)
+	if jvm != nil && jvm.HeapPercentage != nil {
+		percentage = int64(*jvm.HeapPercentage)
+	}
+	resources := serverResources(server)
+	memory, ok := resources.Limits[corev1.ResourceMemory]
+	if !ok {
+		memory, ok = resources.Requests[corev1.ResourceMemory]
+	}
+	if !ok {
+		return 0
+	}
+	return memory.Value() * percentage / 100 / mebibyte
+}
+
+// javaVersion is the major version of Java the server runs with.
+func javaVersion(server *minecraftv1alpha1.MinecraftServer) int {
+	return server.JavaVersion()
+}
+
+// javaImage is the container image to run Java with. If the image has been overridden we have to trust that it has the
+// right version of Java in it.
+func javaImage(server *minecraftv1alpha1.MinecraftServer) string {
+	if server.Spec.JVM != nil && server.Spec.JVM.Image != "" {
+		return server.Spec.JVM.Image
+	}
+	return "eclipse-temurin:" + strconv.Itoa(javaVersion(server))
+}
+
+// jvmArgs are the arguments to pass to the JVM, not including the main class or JAR to run.
+func jvmArgs(server *minecraftv1alpha1.MinecraftServer) ([]string, error) {
+	profile := jvmflags.ProfileDefault
+	jvm := server.Spec.JVM
+	if jvm != nil && jvm.Profile != "" {
+		profile = jvmflags.Profile(jvm.Profile)
+	}
+	args, err := jvmflags.Flags(profile, javaVersion(server), heapSizeMiB(server))
+	if err != nil {
+		return nil, err
+	}
+
+	if jvm == nil {
+		return args, nil
+	}
+	switch jvm.GarbageCollector {
+	case minecraftv1alpha1.GarbageCollectorG1:
+		args = append(args, "-XX:+UseG1GC")
+	case minecraftv1alpha1.GarbageCollectorZGC:
+		args = append(args, "-XX:+UseZGC")
+	case minecraftv1alpha1.GarbageCollectorShenandoah:
+		args = append(args, "-XX:+UseShenandoahGC")
+	case minecraftv1alpha1.GarbageCollectorParallel:
+		args = append(args, "-XX:+UseParallelGC")
+	}
+	return append(args, jvm.ExtraArgs...), nil
+}
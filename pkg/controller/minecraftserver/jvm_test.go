@@ -0,0 +1,126 @@
+package minecraftserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func TestJVMArgs(t *testing.T) {
+	memory := func(limit, request string) *corev1.ResourceRequirements {
+		r := &corev1.ResourceRequirements{Limits: corev1.ResourceList{}, Requests: corev1.ResourceList{}}
+		if limit != "" {
+			r.Limits[corev1.ResourceMemory] = resource.MustParse(limit)
+		}
+		if request != "" {
+			r.Requests[corev1.ResourceMemory] = resource.MustParse(request)
+		}
+		return r
+	}
+	heap := resource.MustParse("3Gi")
+	percentage := int32(50)
+
+	tests := []struct {
+		name      string
+		resources *corev1.ResourceRequirements
+		jvm       *v1alpha1.JVMSpec
+		expected  []string
+	}{
+		{
+			name:      "from limit",
+			resources: memory("8Gi", "4Gi"),
+			expected:  []string{"-Xms6144M", "-Xmx6144M"},
+		},
+		{
+			name:      "from request",
+			resources: memory("", "4Gi"),
+			expected:  []string{"-Xms3072M", "-Xmx3072M"},
+		},
+		{
+			name:      "no memory",
+			resources: memory("", ""),
+			expected:  nil,
+		},
+		{
+			name:      "default resources",
+			resources: nil,
+			expected:  []string{"-Xms4608M", "-Xmx4608M"},
+		},
+		{
+			name:      "absolute heap",
+			resources: memory("8Gi", ""),
+			jvm:       &v1alpha1.JVMSpec{Heap: &heap},
+			expected:  []string{"-Xms3072M", "-Xmx3072M"},
+		},
+		{
+			name:      "heap percentage",
+			resources: memory("8Gi", ""),
+			jvm:       &v1alpha1.JVMSpec{HeapPercentage: &percentage},
+			expected:  []string{"-Xms4096M", "-Xmx4096M"},
+		},
+		{
+			name:      "profile",
+			resources: memory("", ""),
+			jvm:       &v1alpha1.JVMSpec{Profile: v1alpha1.JVMProfileShenandoah},
+			expected: []string{
+				"-XX:+UseShenandoahGC",
+				"-XX:+AlwaysPreTouch",
+				"-XX:+DisableExplicitGC",
+				"-XX:+PerfDisableSharedMem",
+			},
+		},
+		{
+			name:      "garbage collector and extra args",
+			resources: memory("", ""),
+			jvm: &v1alpha1.JVMSpec{
+				GarbageCollector: v1alpha1.GarbageCollectorZGC,
+				ExtraArgs:        []string{"-XX:+AlwaysPreTouch"},
+			},
+			expected: []string{"-XX:+UseZGC", "-XX:+AlwaysPreTouch"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := generateTestServer()
+			server.Spec.Resources = tt.resources
+			server.Spec.JVM = tt.jvm
+			args, err := jvmArgs(&server)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestJavaImage(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.MinecraftVersion = "1.20.6"
+	assert.Equal(t, "eclipse-temurin:21", javaImage(&server))
+
+	server.Spec.MinecraftVersion = "1.16.5"
+	assert.Equal(t, "eclipse-temurin:8", javaImage(&server))
+
+	server.Spec.JVM = &v1alpha1.JVMSpec{Image: "example.com/java:11"}
+	assert.Equal(t, "example.com/java:11", javaImage(&server))
+}
+
+func TestJVMArgsDeclaredJavaVersion(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.MinecraftVersion = "1.16.5"
+	server.Spec.JVM = &v1alpha1.JVMSpec{Profile: v1alpha1.JVMProfileShenandoah}
+	_, err := jvmArgs(&server)
+	assert.Error(t, err)
+
+	// The image has a newer Java than Minecraft 1.16.5 would get by default
+	javaVersion := int32(17)
+	server.Spec.JVM.Image = "example.com/java:17"
+	server.Spec.JVM.JavaVersion = &javaVersion
+	args, err := jvmArgs(&server)
+	require.NoError(t, err)
+	assert.Contains(t, args, "-XX:+UseShenandoahGC")
+	assert.Equal(t, "example.com/java:17", javaImage(&server))
+}
@@ -0,0 +1,110 @@
+package minecraftserver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
+)
+
+// statusRefreshInterval is how often we go back to a running server to refresh the players and other details in the
+// status.
+const statusRefreshInterval = time.Second * 30
+
+// rconTimeout is how long we'll wait for a server to respond to an RCON command when checking on it
+const rconTimeout = time.Second * 5
+
+// RCONClient is a connection to a server's RCON port. See rcon.Client.
+type RCONClient interface {
+	Command(ctx context.Context, command string) (string, error)
+	Close() error
+}
+
+// RCONDialFunc connects to the RCON server at the given address. See rcon.Dial.
+type RCONDialFunc func(ctx context.Context, address, password string) (RCONClient, error)
+
+// reportServerDetails records what the server told us about itself in the status. A nil status means we couldn't
+// reach the server, so we clear out anything we knew before rather than show stale information.
+func reportServerDetails(server *minecraftv1alpha1.MinecraftServer, status *slp.Status, names []string) {
+	if status == nil {
+		server.Status.Players = nil
+		server.Status.ReportedVersion = ""
+		server.Status.MOTD = ""
+		return
+	}
+
+	if names == nil && len(status.Players.Sample) == status.Players.Online {
+		// The sample in the ping is limited to a handful of players, but if everyone fits in it then it's complete.
+		for _, p := range status.Players.Sample {
+			names = append(names, p.Name)
+		}
+	}
+	sort.Strings(names)
+	server.Status.Players = &minecraftv1alpha1.PlayersStatus{
+		Online: int32(status.Players.Online),
+		Max:    int32(status.Players.Max),
+		Names:  names,
+	}
+	server.Status.ReportedVersion = status.Version.Name
+	server.Status.MOTD = status.Description.Text
+}
+
+// listPlayers asks the server who is online over RCON. Unlike a ping this gets everyone, not just a sample.
+func listPlayers(ctx context.Context, k8s client.Client, dial RCONDialFunc, server *minecraftv1alpha1.MinecraftServer, pod *corev1.Pod) ([]string, error) {
+	var secret corev1.Secret
+	err := k8s.Get(ctx, client.ObjectKey{Name: RCONSecretNameForServer(server), Namespace: server.Namespace}, &secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "error performing GET on RCON Secret")
+	}
+	if !PodHasCurrentRCONPassword(pod, &secret) {
+		// This is expected for a little while after a rotation, the Pod is replaced once the workload catches up
+		logutil.FromContextOrNew(ctx).Info("RCON password has been rotated since the Pod started, not listing players")
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rconTimeout)
+	defer cancel()
+	conn, err := dial(ctx, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(rconPort)), string(secret.Data[RCONPasswordSecretKey]))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	response, err := conn.Command(ctx, "list")
+	if err != nil {
+		return nil, err
+	}
+	return rcon.ParseListResponse(response)
+}
+
+// observeServer checks on the server running in the given Pod, and reports what we find in the status. Errors are
+// recorded in the status rather than returned, as a server that isn't responding isn't a reconciliation failure.
+func (r *MinecraftServerReconciler) observeServer(ctx context.Context, server *minecraftv1alpha1.MinecraftServer, pod *corev1.Pod) {
+	status := pingServer(ctx, r.Ping, server, pod)
+	if status == nil {
+		reportServerDetails(server, nil, nil)
+		return
+	}
+
+	var names []string
+	if r.DialRCON != nil {
+		var err error
+		names, err = listPlayers(ctx, r.Client, r.DialRCON, server, pod)
+		if err != nil {
+			// We can still report what we got from the ping
+			logutil.FromContextOrNew(ctx).With(zap.Error(err)).Info("Failed to list players over RCON")
+		}
+	}
+	reportServerDetails(server, status, names)
+}
@@ -0,0 +1,96 @@
+package minecraftserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
+)
+
+func TestReportServerDetails(t *testing.T) {
+	status := &slp.Status{
+		Version:     slp.Version{Name: "Paper 1.19.2", Protocol: 760},
+		Players:     slp.Players{Max: 20, Online: 2, Sample: []slp.Player{{Name: "Bob"}, {Name: "Alice"}}},
+		Description: slp.Description{Text: "A Minecraft Server"},
+	}
+
+	t.Run("names from sample", func(t *testing.T) {
+		server := generateTestServer()
+		reportServerDetails(&server, status, nil)
+		assert.Equal(t, &v1alpha1.PlayersStatus{Online: 2, Max: 20, Names: []string{"Alice", "Bob"}}, server.Status.Players)
+		assert.Equal(t, "Paper 1.19.2", server.Status.ReportedVersion)
+		assert.Equal(t, "A Minecraft Server", server.Status.MOTD)
+	})
+
+	t.Run("incomplete sample", func(t *testing.T) {
+		server := generateTestServer()
+		partial := *status
+		partial.Players.Online = 30
+		reportServerDetails(&server, &partial, nil)
+		assert.Equal(t, &v1alpha1.PlayersStatus{Online: 30, Max: 20}, server.Status.Players)
+	})
+
+	t.Run("names from rcon", func(t *testing.T) {
+		server := generateTestServer()
+		reportServerDetails(&server, status, []string{"Carol", "Alice"})
+		assert.Equal(t, []string{"Alice", "Carol"}, server.Status.Players.Names)
+	})
+
+	t.Run("not responding", func(t *testing.T) {
+		server := generateTestServer()
+		reportServerDetails(&server, status, nil)
+		reportServerDetails(&server, nil, nil)
+		assert.Nil(t, server.Status.Players)
+		assert.Empty(t, server.Status.ReportedVersion)
+		assert.Empty(t, server.Status.MOTD)
+	})
+}
+
+// fakeRCON answers every command with the same response.
+type fakeRCON string
+
+func (f fakeRCON) Command(context.Context, string) (string, error) { return string(f), nil }
+func (f fakeRCON) Close() error                                    { return nil }
+
+func TestListPlayers(t *testing.T) {
+	server := generateTestServer()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        RCONSecretNameForServer(&server),
+			Namespace:   server.Namespace,
+			Annotations: map[string]string{rconPasswordRotationAnnotation: "2"},
+		},
+		Data: map[string][]byte{RCONPasswordSecretKey: []byte("new")},
+	}
+	k8s := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	var dialledWith string
+	dial := func(_ context.Context, _, password string) (RCONClient, error) {
+		dialledWith = password
+		return fakeRCON("There are 1 of a max of 20 players online: Alice"), nil
+	}
+
+	t.Run("current password", func(t *testing.T) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{rconPasswordRotationAnnotation: "2"}}}
+		names, err := listPlayers(context.Background(), k8s, dial, &server, pod)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice"}, names)
+		assert.Equal(t, "new", dialledWith)
+	})
+
+	t.Run("pod started before rotation", func(t *testing.T) {
+		dialledWith = ""
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{rconPasswordRotationAnnotation: "1"}}}
+		names, err := listPlayers(context.Background(), k8s, dial, &server, pod)
+		require.NoError(t, err)
+		assert.Nil(t, names)
+		assert.Empty(t, dialledWith, "shouldn't try the new password against the old Pod")
+	})
+}
@@ -0,0 +1,46 @@
+package minecraftserver
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+// applyPodTemplate merges the server's spec.podTemplate into the generated Pod template using a strategic merge patch,
+// so lists like containers and volumes are merged by name rather than replaced.
+func applyPodTemplate(server *minecraftv1alpha1.MinecraftServer, template *corev1.PodTemplateSpec) error {
+	if server.Spec.PodTemplate == nil {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return errors.Wrap(err, "error encoding pod template")
+	}
+	patch, err := json.Marshal(server.Spec.PodTemplate)
+	if err != nil {
+		return errors.Wrap(err, "error encoding spec.podTemplate")
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+	if err != nil {
+		return errors.Wrap(err, "error applying spec.podTemplate")
+	}
+
+	var result corev1.PodTemplateSpec
+	if err := json.Unmarshal(merged, &result); err != nil {
+		return errors.Wrap(err, "error decoding pod template")
+	}
+	// The ReplicaSet's selector relies on these, so they always take the operator's values.
+	if result.Labels == nil {
+		result.Labels = make(map[string]string)
+	}
+	for k, v := range podLabels(server) {
+		result.Labels[k] = v
+	}
+	*template = result
+	return nil
+}
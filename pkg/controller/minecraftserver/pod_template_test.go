@@ -0,0 +1,91 @@
+package minecraftserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func TestApplyPodTemplate(t *testing.T) {
+	server := generateTestServer()
+	generated := func() corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      podLabels(&server),
+				Annotations: map[string]string{configHashAnnotation: "abc"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "minecraft",
+					Image: "eclipse-temurin:17",
+					Env:   []corev1.EnvVar{{Name: "RCON_PASSWORD", Value: "hunter2"}},
+				}},
+				Volumes: []corev1.Volume{{Name: "config"}},
+			},
+		}
+	}
+
+	t.Run("no overlay", func(t *testing.T) {
+		template := generated()
+		require.NoError(t, applyPodTemplate(&server, &template))
+		assert.Equal(t, generated(), template)
+	})
+
+	t.Run("overlay", func(t *testing.T) {
+		server := server
+		server.Spec.PodTemplate = &v1alpha1.PodTemplate{
+			Metadata: v1alpha1.PodMetadata{
+				Labels:      map[string]string{"team": "minecraft", "app": "something-else"},
+				Annotations: map[string]string{"example.com/scrape": "true"},
+			},
+			Spec: v1alpha1.PodTemplatePodSpec{
+				NodeSelector:      map[string]string{"node.example.com/cpu": "high-clock"},
+				PriorityClassName: "game-servers",
+				Containers: []v1alpha1.ContainerOverlay{
+					{Container: corev1.Container{Name: "minecraft", Env: []corev1.EnvVar{{Name: "TZ", Value: "Europe/London"}}}},
+					{Container: corev1.Container{Name: "log-shipper", Image: "fluent/fluent-bit:2.1"}},
+				},
+			},
+		}
+		template := generated()
+		require.NoError(t, applyPodTemplate(&server, &template))
+
+		assert.Equal(t, "minecraft", template.Labels["team"])
+		assert.Equal(t, "minecraft", template.Labels["app"])
+		assert.Equal(t, "abc", template.Annotations[configHashAnnotation])
+		assert.Equal(t, "true", template.Annotations["example.com/scrape"])
+		assert.Equal(t, map[string]string{"node.example.com/cpu": "high-clock"}, template.Spec.NodeSelector)
+		assert.Equal(t, "game-servers", template.Spec.PriorityClassName)
+		assert.Equal(t, []corev1.Volume{{Name: "config"}}, template.Spec.Volumes)
+
+		require.Len(t, template.Spec.Containers, 2)
+		assert.Equal(t, "eclipse-temurin:17", template.Spec.Containers[0].Image)
+		assert.ElementsMatch(t, []corev1.EnvVar{
+			{Name: "RCON_PASSWORD", Value: "hunter2"},
+			{Name: "TZ", Value: "Europe/London"},
+		}, template.Spec.Containers[0].Env)
+		assert.Contains(t, template.Spec.Containers, corev1.Container{Name: "log-shipper", Image: "fluent/fluent-bit:2.1"})
+	})
+	t.Run("decoded overlay", func(t *testing.T) {
+		server := server
+		server.Spec.PodTemplate = &v1alpha1.PodTemplate{}
+		require.NoError(t, json.Unmarshal([]byte(`{"spec": {
+  "containers": [{"name": "log-shipper", "image": "fluent/fluent-bit:2.1"}],
+  "volumes": [{"name": "logs", "emptyDir": {}}]
+}}`), server.Spec.PodTemplate))
+		template := generated()
+		require.NoError(t, applyPodTemplate(&server, &template))
+
+		assert.Contains(t, template.Spec.Containers, corev1.Container{Name: "log-shipper", Image: "fluent/fluent-bit:2.1"})
+		assert.Contains(t, template.Spec.Volumes, corev1.Volume{
+			Name:         "logs",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	})
+}
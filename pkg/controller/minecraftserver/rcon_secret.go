@@ -0,0 +1,124 @@
+package minecraftserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+)
+
+// RCONPasswordSecretKey is the key in the RCON Secret that holds the password.
+const RCONPasswordSecretKey = "password"
+
+// RotateRCONPasswordAnnotation can be set on a MinecraftServer to rotate the RCON password. Whenever the value changes
+// a new password is generated and the server is restarted to use it. Any value will do, but a timestamp is a good
+// choice.
+const RotateRCONPasswordAnnotation = "minecraft.jameslaverack.com/rotate-rcon-password"
+
+// rconPasswordRotationAnnotation is set on the RCON Secret to record the value of RotateRCONPasswordAnnotation that the
+// password was last generated for.
+const rconPasswordRotationAnnotation = "minecraft.jameslaverack.com/rcon-password-rotation"
+
+func RCONSecretNameForServer(server *minecraftv1alpha1.MinecraftServer) string {
+	return server.Name + "-rcon"
+}
+
+func RCONSecret(ctx context.Context, k8s client.Client, server *minecraftv1alpha1.MinecraftServer) (bool, error) {
+	log := logutil.FromContextOrNew(ctx).With(zap.String("rcon-secret-name", RCONSecretNameForServer(server)))
+	requestedRotation := server.Annotations[RotateRCONPasswordAnnotation]
+
+	var actualSecret corev1.Secret
+	err := k8s.Get(ctx, client.ObjectKey{Name: RCONSecretNameForServer(server), Namespace: server.Namespace}, &actualSecret)
+	if apierrors.IsNotFound(err) {
+		log.Info("RCON Secret does not exist, creating")
+		password, err := generateRCONPassword()
+		if err != nil {
+			return false, err
+		}
+		expectedSecret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            RCONSecretNameForServer(server),
+				Namespace:       server.Namespace,
+				OwnerReferences: []metav1.OwnerReference{serverOwnerReference(server)},
+				Annotations: map[string]string{
+					rconPasswordRotationAnnotation: requestedRotation,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				RCONPasswordSecretKey: []byte(password),
+			},
+		}
+		return true, k8s.Create(ctx, &expectedSecret)
+	} else if err != nil {
+		return false, errors.Wrap(err, "error performing GET on Secret")
+	}
+
+	if !hasCorrectOwnerReference(server, &actualSecret) {
+		log.Info("RCON Secret owner references incorrect, updating")
+		actualSecret.OwnerReferences = append(actualSecret.OwnerReferences, serverOwnerReference(server))
+		return true, k8s.Update(ctx, &actualSecret)
+	}
+
+	if len(actualSecret.Data[RCONPasswordSecretKey]) == 0 ||
+		actualSecret.Annotations[rconPasswordRotationAnnotation] != requestedRotation {
+		log.Info("RCON password missing or rotation requested, generating new password")
+		password, err := generateRCONPassword()
+		if err != nil {
+			return false, err
+		}
+		if actualSecret.Data == nil {
+			actualSecret.Data = make(map[string][]byte)
+		}
+		actualSecret.Data[RCONPasswordSecretKey] = []byte(password)
+		if actualSecret.Annotations == nil {
+			actualSecret.Annotations = make(map[string]string)
+		}
+		actualSecret.Annotations[rconPasswordRotationAnnotation] = requestedRotation
+		return true, k8s.Update(ctx, &actualSecret)
+	}
+
+	log.Debug("RCON Secret OK")
+	return false, nil
+}
+
+// PodHasCurrentRCONPassword is false if the RCON password in the Secret was rotated after the given server Pod started.
+// The Pod keeps using the old password until it's replaced, so anything that uses the password from the Secret will
+// fail to authenticate until then.
+func PodHasCurrentRCONPassword(pod *corev1.Pod, secret *corev1.Secret) bool {
+	return pod.Annotations[rconPasswordRotationAnnotation] == secret.Annotations[rconPasswordRotationAnnotation]
+}
+
+// generateRCONPassword makes a new random password. We hex encode it so that it's safe to put in server.properties
+// without any escaping.
+func generateRCONPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate RCON password")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// rconPasswordEnvVar is an environment variable, RCON_PASSWORD, that has the RCON password for the server in it.
+func rconPasswordEnvVar(server *minecraftv1alpha1.MinecraftServer) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "RCON_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: RCONSecretNameForServer(server),
+				},
+				Key: RCONPasswordSecretKey,
+			},
+		},
+	}
+}
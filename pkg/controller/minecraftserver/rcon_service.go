@@ -85,7 +85,7 @@ func RCONServiceForServer(server *minecraftv1alpha1.MinecraftServer) corev1.Serv
 			Ports: []corev1.ServicePort{
 				{
 					Name:     "rcon",
-					Port:     25575,
+					Port:     rconPort,
 					Protocol: corev1.ProtocolTCP,
 				},
 			},
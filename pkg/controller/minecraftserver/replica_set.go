@@ -2,14 +2,17 @@ package minecraftserver
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,23 +21,35 @@ import (
 	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/bibliothek"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/purpur"
 	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/vanillatweaks"
 )
 
-func ReplicaSet(ctx context.Context, k8s client.Client, server *minecraftv1alpha1.MinecraftServer) (bool, error) {
+func ReplicaSet(ctx context.Context, k8s client.Client, sources *Sources, server *minecraftv1alpha1.MinecraftServer) (bool, error) {
 	log := logutil.FromContextOrNew(ctx)
-	expectedPS, err := rsForServer(ctx, server)
+
+	var actualRS appsv1.ReplicaSet
+	err := k8s.Get(ctx, client.ObjectKey{Name: server.Name, Namespace: server.Namespace}, &actualRS)
+	if client.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, "error performing GET on ReplicaSet")
+	}
+	exists := err == nil
+
+	// Unless the server type or Minecraft version has changed we stick with the build we're already running.
+	// Otherwise, every time upstream publishes a new build we'd restart the server to pick it up.
+	pinnedBuild := ""
+	if exists {
+		pinnedBuild = pinnedBuildFor(server, actualRS.Annotations)
+	}
+
+	expectedRS, err := rsForServer(ctx, sources, server, pinnedBuild)
 	if err != nil {
 		return false, err
 	}
 
-	var actualRS appsv1.ReplicaSet
-	err = k8s.Get(ctx, client.ObjectKeyFromObject(&expectedPS), &actualRS)
-	if apierrors.IsNotFound(err) {
+	if !exists {
 		log.Info("ReplicaSet does not exist, creating")
-		return true, k8s.Create(ctx, &expectedPS)
-	} else if err != nil {
-		return false, errors.Wrap(err, "error performing GET on ReplicaSet")
+		return true, k8s.Create(ctx, &expectedRS)
 	}
 
 	if !hasCorrectOwnerReference(server, &actualRS) {
@@ -44,19 +59,98 @@ func ReplicaSet(ctx context.Context, k8s client.Client, server *minecraftv1alpha
 		return true, k8s.Update(ctx, &actualRS)
 	}
 
+	// A ReplicaSet doesn't replace its Pods when the template changes, so we have to do it ourselves. We can't just
+	// delete the Pod, as the ReplicaSet would create the replacement straight away and we'd have two servers trying to
+	// use the same world. Instead, we update the template and scale to zero in one go, and then scale back up only once
+	// the old Pod is completely gone.
+	if actualRS.Annotations[podTemplateHashAnnotation] != expectedRS.Annotations[podTemplateHashAnnotation] {
+		log.With(
+			zap.String("actual-hash", actualRS.Annotations[podTemplateHashAnnotation]),
+			zap.String("expected-hash", expectedRS.Annotations[podTemplateHashAnnotation])).
+			Info("ReplicaSet pod template out of date, stopping server to replace it")
+		if actualRS.Annotations == nil {
+			actualRS.Annotations = make(map[string]string)
+		}
+		for k, v := range expectedRS.Annotations {
+			actualRS.Annotations[k] = v
+		}
+		actualRS.Spec.Template = expectedRS.Spec.Template
+		actualRS.Spec.Replicas = pointer.Int32(0)
+		return true, k8s.Update(ctx, &actualRS)
+	}
+
+	if actualRS.Spec.Replicas != nil && *actualRS.Spec.Replicas != *expectedRS.Spec.Replicas {
+		var pods corev1.PodList
+		err = k8s.List(ctx, &pods, client.InNamespace(server.Namespace), client.MatchingLabels(podLabels(server)))
+		if err != nil {
+			return false, errors.Wrap(err, "error listing Pods")
+		}
+		if len(pods.Items) > 0 {
+			// We'll be triggered again when the Pod is deleted
+			log.With(zap.Int("pods", len(pods.Items))).Info("Waiting for old server Pod to terminate")
+			return true, nil
+		}
+		log.Info("ReplicaSet scaled incorrectly, updating")
+		actualRS.Spec.Replicas = expectedRS.Spec.Replicas
+		return true, k8s.Update(ctx, &actualRS)
+	}
+
+	server.Status.ServerBuild = actualRS.Annotations[serverBuildAnnotation]
+
 	log.Debug("ReplicaSet OK")
 	return false, nil
 }
 
+// podTemplateHash computes a hash of the given pod template, to be used to detect if the running server is out of date.
+func podTemplateHash(server *minecraftv1alpha1.MinecraftServer, template *corev1.PodTemplateSpec) (string, error) {
+	// The VanillaTweaks API generates a new download link every time we ask for one, so if we hashed it we'd restart the
+	// server on every reconcile. Hash the datapacks that were asked for instead.
+	t := template.DeepCopy()
+	for i := range t.Spec.InitContainers {
+		if t.Spec.InitContainers[i].Name == vanillaTweaksContainerName {
+			t.Spec.InitContainers[i].Args = nil
+		}
+	}
+	d, err := json.Marshal(struct {
+		Template      *corev1.PodTemplateSpec
+		VanillaTweaks *minecraftv1alpha1.VanillaTweaks
+	}{t, server.Spec.VanillaTweaks})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(d)), nil
+}
+
+// configHash computes a hash of the server's config files.
+func configHash(server *minecraftv1alpha1.MinecraftServer) (string, error) {
+	data, err := configMapData(*server)
+	if err != nil {
+		return "", err
+	}
+	// Go's JSON encoding of a map has sorted keys, so this is stable.
+	d, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(d)), nil
+}
+
 // Spigot really, *really* wants to be able to write to its config files. So we copy them over from the configmap to
-// the Spigot server's working directory in /run/minecraft to let it run all over them.
-// TODO Handle live changes to these files, maybe with some kind of sidecar?
-func copyConfigContainer(configVolumeMountName, paperWorkingDirVolumeName string) corev1.Container {
+// the Spigot server's working directory in /run/minecraft to let it run all over them. Changes to the config are picked
+// up by restarting the server, as the config hash is part of the pod template.
+//
+// This is also where the RCON password gets added to server.properties, so that it never has to be in the ConfigMap.
+func copyConfigContainer(server *minecraftv1alpha1.MinecraftServer, configVolumeMountName, paperWorkingDirVolumeName string) corev1.Container {
 	return corev1.Container{
 		Name:  "copy-config",
 		Image: "busybox",
-		// We use sh here to get file globbing with the *.
-		Args: []string{"sh", "-c", "cp /etc/minecraft/* /run/minecraft/"},
+		// We use sh here to get file globbing with the *. The glob skips the dot-prefixed "..data" entries that
+		// Kubernetes uses to update ConfigMap volumes. The visible files are themselves symlinks into "..data", but cp
+		// follows them and copies the current contents. If the working directory already has older versions of them in
+		// it we overwrite them.
+		Args: []string{"sh", "-c", "cp -f /etc/minecraft/* /run/minecraft/ && " +
+			"echo \"rcon.password=${RCON_PASSWORD}\" >> /run/minecraft/server.properties"},
+		Env: []corev1.EnvVar{rconPasswordEnvVar(server)},
 		VolumeMounts: []corev1.VolumeMount{
 			// This will mount config files, like server.properties, under /etc/minecraft/server.properties
 			{
@@ -90,14 +184,16 @@ func copyDynmapConfigContainer(dynmapConfigVolumeMountName, dynmapDataVolumeName
 	}
 }
 
+const vanillaTweaksContainerName = "install-vanillatweaks"
+
 func vanillaTweaksDatapackContainer(ctx context.Context, datapacksVolumeMountName string, version string, tweaks *minecraftv1alpha1.VanillaTweaks) (corev1.Container, error) {
 	url, err := vanillatweaks.GetDatapackDownloadURL(ctx, version, tweaks.Datapacks)
 	if err != nil {
-		return corev1.Container{}, err
+		return corev1.Container{}, errors.Wrap(err, "failed to get VanillaTweaks datapack download URL")
 	}
 
 	return corev1.Container{
-		Name:  "install-vanillatweaks",
+		Name:  vanillaTweaksContainerName,
 		Image: "busybox",
 		Args:  []string{"sh", "-c", "cd /var/minecraft/world/datapacks && wget -O vt.zip '" + url + "' && unzip vt.zip && rm vt.zip"},
 		VolumeMounts: []corev1.VolumeMount{
@@ -109,6 +205,30 @@ func vanillaTweaksDatapackContainer(ctx context.Context, datapacksVolumeMountNam
 	}, nil
 }
 
+// managedPlugins are the plugins that the operator can install, by the name of their JAR in the plugins directory.
+var managedPlugins = []string{"dynmap"}
+
+// removeManagedPluginsContainer deletes the JARs of every plugin the operator can install, so that only the ones that
+// are enabled are installed again. Otherwise, if the plugins directory is persisted, a plugin that's been disabled
+// would still be there and keep being loaded.
+func removeManagedPluginsContainer(pluginsVolumeMountName string) corev1.Container {
+	args := []string{"rm", "-f"}
+	for _, p := range managedPlugins {
+		args = append(args, "/usr/local/minecraft/plugins/"+p+".jar")
+	}
+	return corev1.Container{
+		Name:  "remove-managed-plugins",
+		Image: "busybox",
+		Args:  args,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      pluginsVolumeMountName,
+				MountPath: "/usr/local/minecraft/plugins",
+			},
+		},
+	}
+}
+
 func spigetInstallContainer(pluginResourceID, pluginName, pluginsVolumeMountName string) corev1.Container {
 	return corev1.Container{
 		Name:  "install-dynmap",
@@ -151,6 +271,63 @@ func downloadContainer(url, sha256, filename, volumeMountName string) corev1.Con
 	}
 }
 
+// plainDownloadContainer downloads a file without checking it. It's only for files there's no checksum for, as the
+// usual download image can't be given an empty checksum.
+func plainDownloadContainer(url, filename, volumeMountName string) corev1.Container {
+	return corev1.Container{
+		Name:  "download-" + strings.Replace(filename, ".", "-", -1),
+		Image: "busybox",
+		Args:  []string{"sh", "-c", `wget -O "$DOWNLOAD_TARGET" "$DOWNLOAD_URL"`},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      volumeMountName,
+				MountPath: "/download",
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "DOWNLOAD_URL",
+				Value: url,
+			},
+			{
+				Name:  "DOWNLOAD_TARGET",
+				Value: filepath.Join("/download", filename),
+			},
+		},
+	}
+}
+
+// checksumDownloadContainer downloads a file and checks it with the given busybox checksum tool, e.g., "sha1" for
+// sha1sum. Not everywhere publishes SHA-256 checksums, so we can't always use the usual download image.
+func checksumDownloadContainer(url, algorithm, checksum, filename, volumeMountName string) corev1.Container {
+	return corev1.Container{
+		Name:  "download-" + strings.Replace(filename, ".", "-", -1),
+		Image: "busybox",
+		Args: []string{"sh", "-c",
+			`wget -O "$DOWNLOAD_TARGET" "$DOWNLOAD_URL" && echo "$DOWNLOAD_CHECKSUM  $DOWNLOAD_TARGET" | ` + algorithm + `sum -c -`},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      volumeMountName,
+				MountPath: "/download",
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "DOWNLOAD_URL",
+				Value: url,
+			},
+			{
+				Name:  "DOWNLOAD_TARGET",
+				Value: filepath.Join("/download", filename),
+			},
+			{
+				Name:  "DOWNLOAD_CHECKSUM",
+				Value: checksum,
+			},
+		},
+	}
+}
+
 func SecurityContext() *corev1.SecurityContext {
 	return &corev1.SecurityContext{
 		Privileged:               pointer.Bool(false),
@@ -162,77 +339,198 @@ func SecurityContext() *corev1.SecurityContext {
 	}
 }
 
-func rsForServer(ctx context.Context, server *v1alpha1.MinecraftServer) (appsv1.ReplicaSet, error) {
+// podSecurityContext makes sure the server can write to its persistent volumes, which are often only writeable by root
+// when they're first provisioned. Only changing ownership on a mismatch saves going through an entire world on every
+// start.
+func podSecurityContext() *corev1.PodSecurityContext {
+	policy := corev1.FSGroupChangeOnRootMismatch
+	return &corev1.PodSecurityContext{
+		FSGroup:             pointer.Int64(1000),
+		FSGroupChangePolicy: &policy,
+	}
+}
+
+// rsForServer generates the ReplicaSet for the server. If pinnedBuild is set, it's used as the build of the server
+// software instead of resolving the latest one.
+func rsForServer(ctx context.Context, sources *Sources, server *v1alpha1.MinecraftServer, pinnedBuild string) (appsv1.ReplicaSet, error) {
+	var rs appsv1.ReplicaSet
+	var err error
 	switch server.Spec.Type {
-	case minecraftv1alpha1.ServerTypePaper:
-		return rsForServerTypePaper(ctx, server)
+	case minecraftv1alpha1.ServerTypePaper, minecraftv1alpha1.ServerTypeFolia, minecraftv1alpha1.ServerTypePurpur:
+		rs, err = rsForServerTypePaper(ctx, sources, server, pinnedBuild)
 	case minecraftv1alpha1.ServerTypeForge:
-		return rsForServerTypeForge(ctx, server)
+		rs, err = rsForServerTypeForge(ctx, server)
+	case minecraftv1alpha1.ServerTypeNeoForge:
+		rs, err = rsForServerTypeNeoForge(ctx, sources.NeoForge, server, pinnedBuild)
+	case minecraftv1alpha1.ServerTypeVanilla:
+		rs, err = rsForServerTypeVanilla(ctx, sources.Mojang, server)
+	case minecraftv1alpha1.ServerTypeFabric:
+		rs, err = rsForServerTypeFabric(ctx, sources.FabricMeta, server, pinnedBuild)
+	case minecraftv1alpha1.ServerTypeBedrock:
+		rs, err = rsForServerTypeBedrock(server)
 	default:
 		return appsv1.ReplicaSet{}, errors.New("Unrecognised server type")
 	}
-}
+	if err != nil {
+		return appsv1.ReplicaSet{}, err
+	}
 
-func rsForServerTypePaper(ctx context.Context, server *v1alpha1.MinecraftServer) (appsv1.ReplicaSet, error) {
-	const paperJarVolumeName = "paper-jar"
-	const paperWorkingDirVolumeName = "paper-workingdir"
-	const configVolumeMountName = "config"
-	const overworldMountName = "world-overworld"
-	const netherMountName = "world-nether"
-	const theEndMountName = "world-the-end"
-	const dataPacksMountName = "data-packs"
-	const pluginsMountName = "plugins"
+	if err := applyPodTemplate(server, &rs.Spec.Template); err != nil {
+		return appsv1.ReplicaSet{}, err
+	}
 
-	latestBuild, err := bibliothek.LatestBuildForVersion(server.Spec.MinecraftVersion)
+	configHash, err := configHash(server)
 	if err != nil {
 		return appsv1.ReplicaSet{}, err
 	}
-	url, sha256, err := bibliothek.GetDownloadURLAndSHA256(server.Spec.MinecraftVersion, latestBuild)
+	if rs.Spec.Template.Annotations == nil {
+		rs.Spec.Template.Annotations = make(map[string]string)
+	}
+	rs.Spec.Template.Annotations[configHashAnnotation] = configHash
+	// The RCON password is only read when the Pod starts, so a rotation needs to change the template too.
+	if rotation := server.Annotations[RotateRCONPasswordAnnotation]; rotation != "" {
+		rs.Spec.Template.Annotations[rconPasswordRotationAnnotation] = rotation
+	}
+
+	templateHash, err := podTemplateHash(server, &rs.Spec.Template)
 	if err != nil {
 		return appsv1.ReplicaSet{}, err
 	}
+	if rs.Annotations == nil {
+		rs.Annotations = make(map[string]string)
+	}
+	rs.Annotations[podTemplateHashAnnotation] = templateHash
+	rs.Annotations[minecraftVersionAnnotation] = server.Spec.MinecraftVersion
+	rs.Annotations[serverTypeAnnotation] = string(server.Spec.Type)
+
+	return rs, nil
+}
+
+// pinnedBuildFor finds the build recorded in an existing workload's annotations, if it was resolved for the same server
+// type and Minecraft version as the server now asks for.
+func pinnedBuildFor(server *v1alpha1.MinecraftServer, annotations map[string]string) string {
+	if annotations[serverTypeAnnotation] != string(server.Spec.Type) ||
+		annotations[minecraftVersionAnnotation] != server.Spec.MinecraftVersion {
+		return ""
+	}
+	return annotations[serverBuildAnnotation]
+}
+
+// paperProject is the PaperMC project that provides the server software.
+func paperProject(server *v1alpha1.MinecraftServer) bibliothek.Project {
+	if server.Spec.Type == minecraftv1alpha1.ServerTypeFolia {
+		return bibliothek.ProjectFolia
+	}
+	return bibliothek.ProjectPaper
+}
+
+// paperChannel is the least stable channel of builds we'll run for a project. Unless the spec says otherwise, Folia uses
+// the experimental channel as it's published there for most versions, so it'd be unusable otherwise.
+func paperChannel(project bibliothek.Project, server *v1alpha1.MinecraftServer) bibliothek.Channel {
+	if server.Spec.Paper != nil {
+		switch server.Spec.Paper.Channel {
+		case v1alpha1.PaperChannelDefault:
+			return bibliothek.ChannelDefault
+		case v1alpha1.PaperChannelExperimental:
+			return bibliothek.ChannelExperimental
+		}
+	}
+	if project == bibliothek.ProjectFolia {
+		return bibliothek.ChannelExperimental
+	}
+	return bibliothek.ChannelDefault
+}
+
+// paperBuild is the pinned build of a PaperMC project if there is one, and otherwise the latest build.
+func paperBuild(ctx context.Context, paperClient *bibliothek.Client, project bibliothek.Project, server *v1alpha1.MinecraftServer, pinnedBuild string) (bibliothek.Build, error) {
+	if pinnedBuild != "" {
+		b, err := strconv.Atoi(pinnedBuild)
+		if err != nil {
+			return bibliothek.Build{}, errors.Wrapf(err, "invalid %s build %q", project, pinnedBuild)
+		}
+		return paperClient.Build(ctx, project, server.Spec.MinecraftVersion, b)
+	}
+	b, err := paperClient.LatestBuild(ctx, project, server.Spec.MinecraftVersion, paperChannel(project, server))
+	if err != nil {
+		return bibliothek.Build{}, errors.Wrapf(err, "failed to find latest %s build for Minecraft %s", project, server.Spec.MinecraftVersion)
+	}
+	return b, nil
+}
 
-	paperDownloadContainer := downloadContainer(url, sha256, "paper.jar", paperJarVolumeName)
-	copyConfigContainer := copyConfigContainer(configVolumeMountName, paperWorkingDirVolumeName)
+// purpurBuild is the pinned build of Purpur if there is one, and otherwise the latest build.
+func purpurBuild(ctx context.Context, purpurClient *purpur.Client, server *v1alpha1.MinecraftServer, pinnedBuild string) (purpur.Build, error) {
+	if pinnedBuild != "" {
+		return purpurClient.Build(ctx, server.Spec.MinecraftVersion, pinnedBuild)
+	}
+	return purpurClient.LatestBuild(ctx, server.Spec.MinecraftVersion)
+}
+
+// rsForServerTypePaper generates the ReplicaSet for Paper and the servers forked from it, which all take the same
+// flags.
+func rsForServerTypePaper(ctx context.Context, sources *Sources, server *v1alpha1.MinecraftServer, pinnedBuild string) (appsv1.ReplicaSet, error) {
+	const paperJarVolumeName = "paper-jar"
+	const configVolumeMountName = "config"
+	const dataPacksMountName = "data-packs"
 
-	initContainers := []corev1.Container{paperDownloadContainer, copyConfigContainer}
+	var paperDownloadContainer corev1.Container
+	var build string
+	if server.Spec.Type == minecraftv1alpha1.ServerTypePurpur {
+		b, err := purpurBuild(ctx, sources.Purpur, server, pinnedBuild)
+		if err != nil {
+			return appsv1.ReplicaSet{}, err
+		}
+		// Purpur only publishes MD5 checksums
+		paperDownloadContainer = checksumDownloadContainer(sources.Purpur.DownloadURL(b), "md5", b.MD5, "paper.jar", paperJarVolumeName)
+		build = b.Build
+	} else {
+		project := paperProject(server)
+		b, err := paperBuild(ctx, sources.Paper, project, server, pinnedBuild)
+		if err != nil {
+			return appsv1.ReplicaSet{}, err
+		}
+		url, checksum, err := sources.Paper.ApplicationDownload(project, server.Spec.MinecraftVersion, b)
+		if err != nil {
+			return appsv1.ReplicaSet{}, errors.Wrapf(err, "failed to get download for %s build %d", project, b.Build)
+		}
+		paperDownloadContainer = downloadContainer(url, checksum, "paper.jar", paperJarVolumeName)
+		build = strconv.Itoa(b.Build)
+	}
+	copyConfigContainer := copyConfigContainer(server, configVolumeMountName, paperWorkingDirVolumeName)
+
+	initContainers := []corev1.Container{paperDownloadContainer, copyConfigContainer, serverAgentInstallContainer()}
+
+	jvm, err := jvmArgs(server)
+	if err != nil {
+		return appsv1.ReplicaSet{}, err
+	}
+	javaArgs := append([]string{"java"},
+		///////////////////////////////
+		// Flags here are flags to Java
+		///////////////////////////////
+		jvm...)
+	javaArgs = append(javaArgs,
+		"-jar",
+		"/usr/local/minecraft/paper.jar",
+		////////////////////////////////////////////////////////////
+		// Flags after this point are flags to PaperMC, and not Java
+		////////////////////////////////////////////////////////////
+		// Set the world directory to be /var/minecraft
+		"--world-container=/var/minecraft",
+		// Set the plugin directory to be /usr/local/minecraft/plugins
+		"--plugins=/usr/local/minecraft/plugins",
+		// Disable the on-disk logging, we'll use STDOUT logging always
+		"--log-append=false",
+		// Disable the GUI, no need in a container
+		"--nogui")
 
 	mainJavaContainer := corev1.Container{
-		Name: "minecraft",
-		// TODO Configure Java Version
-		Image: "eclipse-temurin:17",
-		Args: []string{
-			"java",
-			///////////////////////////////
-			// Flags here are flags to Java
-			///////////////////////////////
-			"-jar",
-			"/usr/local/minecraft/paper.jar",
-			////////////////////////////////////////////////////////////
-			// Flags after this point are flags to PaperMC, and not Java
-			////////////////////////////////////////////////////////////
-			// Set the world directory to be /var/minecraft
-			"--world-container=/var/minecraft",
-			// Set the plugin directory to be /usr/local/minecraft/plugins
-			"--plugins=/usr/local/minecraft/plugins",
-			// Disable the on-disk logging, we'll use STDOUT logging always
-			"--log-append=false",
-			// Disable the GUI, no need in a container
-			"--nogui"},
+		Name:  "minecraft",
+		Image: javaImage(server),
+		Args:  javaArgs,
 		// Paper expects to be able to write all kinds of stuff to it's working directory, so we give it a dedicated
 		// scratch dir for it's use under /run/minecraft.
 		WorkingDir: "/run/minecraft",
-		// TODO Make resources configurable
-		Resources: corev1.ResourceRequirements{
-			Limits: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse("6Gi"),
-				// No CPU limit to avoid CPU throttling
-			},
-			Requests: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse("2.5Gi"),
-				corev1.ResourceCPU:    resource.MustParse("2"),
-			},
-		},
+		Resources:  serverResources(server),
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "minecraft",
@@ -281,12 +579,18 @@ func rsForServerTypePaper(ctx context.Context, server *v1alpha1.MinecraftServer)
 		},
 	}
 
+	addServerProbes(&mainJavaContainer, 10)
+	addGracefulShutdown(server, &mainJavaContainer)
+
 	var replicas int32 = 1
 	rs := appsv1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            server.Name,
 			Namespace:       server.Namespace,
 			OwnerReferences: []metav1.OwnerReference{serverOwnerReference(server)},
+			Annotations: map[string]string{
+				serverBuildAnnotation: build,
+			},
 		},
 		Spec: appsv1.ReplicaSetSpec{
 			Replicas: &replicas,
@@ -298,6 +602,8 @@ func rsForServerTypePaper(ctx context.Context, server *v1alpha1.MinecraftServer)
 					Labels: podLabels(server),
 				},
 				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: terminationGracePeriodSeconds(server),
+					SecurityContext:               podSecurityContext(),
 					Volumes: []corev1.Volume{
 						{
 							Name: configVolumeMountName,
@@ -315,24 +621,15 @@ func rsForServerTypePaper(ctx context.Context, server *v1alpha1.MinecraftServer)
 								EmptyDir: &corev1.EmptyDirVolumeSource{},
 							},
 						},
-						{
-							Name: paperWorkingDirVolumeName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
+						persistentVolume(paperWorkingDirVolumeName, persistence(server).ServerState),
 						{
 							Name: dataPacksMountName,
 							VolumeSource: corev1.VolumeSource{
 								EmptyDir: &corev1.EmptyDirVolumeSource{},
 							},
 						},
-						{
-							Name: pluginsMountName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
+						persistentVolume(pluginsMountName, persistence(server).PluginData),
+						serverAgentVolume(),
 					},
 				},
 			},
@@ -391,6 +688,7 @@ func rsForServerTypePaper(ctx context.Context, server *v1alpha1.MinecraftServer)
 		initContainers = append(initContainers, vtDownloadContainer)
 	}
 
+	initContainers = append(initContainers, removeManagedPluginsContainer(pluginsMountName))
 	if server.Spec.Dynmap != nil && server.Spec.Dynmap.Enabled {
 		initContainers = append(initContainers, spigetInstallContainer("274", "dynmap", pluginsMountName))
 		mainJavaContainer.Ports = append(mainJavaContainer.Ports,
@@ -412,7 +710,6 @@ func rsForServerTypePaper(ctx context.Context, server *v1alpha1.MinecraftServer)
 				},
 			})
 		// Use an init container to move the mounted dynmap config into the runtime plugins dir
-		const dynmapDataMountName = "dynmap-data"
 		initContainers = append(initContainers, copyDynmapConfigContainer(dynmapConfigMountName, dynmapDataMountName))
 
 		mainJavaContainer.VolumeMounts = append(
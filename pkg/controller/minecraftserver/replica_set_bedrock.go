@@ -0,0 +1,208 @@
+package minecraftserver
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+// defaultBedrockImage is what we run the Bedrock server in, unless the spec says otherwise. The server binary needs
+// glibc and libcurl, which this has without anything else we don't need.
+const defaultBedrockImage = "buildpack-deps:jammy-curl"
+
+// bedrockTerminationGracePeriodSeconds is how long the server has to stop before it's killed. The server saves the
+// world and stops as soon as it gets SIGTERM, with no countdown, so this only needs to cover a save of a large world.
+const bedrockTerminationGracePeriodSeconds = 60
+
+// bedrockDownloadURL is where to download the Bedrock Dedicated Server for Linux. It's true if this is our default,
+// rather than a URL from the spec.
+func bedrockDownloadURL(server *v1alpha1.MinecraftServer) (string, bool) {
+	if server.Spec.Bedrock != nil && server.Spec.Bedrock.DownloadURL != "" {
+		return server.Spec.Bedrock.DownloadURL, false
+	}
+	return "https://www.minecraft.net/bedrockdedicatedserver/bin-linux/bedrock-server-" + server.Spec.MinecraftVersion + ".zip", true
+}
+
+// bedrockInstallScript downloads and extracts the server. The download is only checked if there's a checksum for it.
+func bedrockInstallScript(server *v1alpha1.MinecraftServer) string {
+	// Mojang only allow the server to be downloaded by people who have accepted the EULA
+	script := `if [ "$EULA" != "Accepted" ]; then echo "The Minecraft EULA must be accepted to download the server" >&2; exit 1; fi && `
+	if _, isDefault := bedrockDownloadURL(server); isDefault {
+		// minecraft.net refuses downloads from clients that don't look like a browser
+		script += `wget -U "Mozilla/5.0 (X11; Linux x86_64)" -O /download/bedrock-server.zip "$DOWNLOAD_URL" && `
+	} else {
+		script += `wget -O /download/bedrock-server.zip "$DOWNLOAD_URL" && `
+	}
+	if server.Spec.Bedrock != nil && server.Spec.Bedrock.DownloadSHA256Sum != "" {
+		script += `echo "$DOWNLOAD_SHA256  /download/bedrock-server.zip" | sha256sum -c - && `
+	}
+	return script +
+		`unzip -o -q /download/bedrock-server.zip -d /run/minecraft && ` +
+		`chmod +x /run/minecraft/bedrock_server && ` +
+		`cp -f /etc/minecraft/* /run/minecraft/`
+}
+
+func bedrockImage(server *v1alpha1.MinecraftServer) string {
+	if server.Spec.Bedrock != nil && server.Spec.Bedrock.Image != "" {
+		return server.Spec.Bedrock.Image
+	}
+	return defaultBedrockImage
+}
+
+// rsForServerTypeBedrock generates the ReplicaSet for a Bedrock Dedicated Server. This is a native binary rather than
+// a JAR, and it has no RCON or Server List Ping, so very little is shared with the Java Edition servers.
+func rsForServerTypeBedrock(server *v1alpha1.MinecraftServer) (appsv1.ReplicaSet, error) {
+	const serverZipVolumeName = "bedrock-server-zip"
+	const configVolumeMountName = "config"
+
+	// The server is distributed as a ZIP of the binary along with the resources it needs, all of which it expects to
+	// find in its working directory. We extract it over the top of the working directory, which replaces the server
+	// but leaves the worlds alone, and then copy our config files over the defaults it comes with.
+	downloadURL, _ := bedrockDownloadURL(server)
+	installContainer := corev1.Container{
+		Name:  "install-bedrock-server",
+		Image: "busybox",
+		Args:  []string{"sh", "-c", bedrockInstallScript(server)},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "DOWNLOAD_URL",
+				Value: downloadURL,
+			},
+			{
+				Name:  "EULA",
+				Value: string(server.Spec.EULA),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      serverZipVolumeName,
+				MountPath: "/download",
+			},
+			// This will mount config files, like server.properties, under /etc/minecraft/server.properties
+			{
+				Name:      configVolumeMountName,
+				MountPath: "/etc/minecraft",
+			},
+			{
+				Name:      bedrockWorkingDirVolumeName,
+				MountPath: "/run/minecraft",
+			},
+		},
+	}
+	if server.Spec.Bedrock != nil && server.Spec.Bedrock.DownloadSHA256Sum != "" {
+		installContainer.Env = append(installContainer.Env, corev1.EnvVar{
+			Name:  "DOWNLOAD_SHA256",
+			Value: server.Spec.Bedrock.DownloadSHA256Sum,
+		})
+	}
+
+	mainContainer := corev1.Container{
+		Name:    "minecraft",
+		Image:   bedrockImage(server),
+		Command: []string{"/run/minecraft/bedrock_server"},
+		// The server's shared libraries come in the ZIP alongside it
+		Env: []corev1.EnvVar{
+			{
+				Name:  "LD_LIBRARY_PATH",
+				Value: "/run/minecraft",
+			},
+		},
+		// The server reads commands from stdin, so keeping it open lets them be sent with kubectl attach
+		Stdin:      true,
+		WorkingDir: "/run/minecraft",
+		Resources:  serverResources(server),
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "minecraft",
+				ContainerPort: bedrockPort,
+				Protocol:      corev1.ProtocolUDP,
+			},
+			{
+				Name:          "minecraft-v6",
+				ContainerPort: bedrockPortV6,
+				Protocol:      corev1.ProtocolUDP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      bedrockWorkingDirVolumeName,
+				MountPath: "/run/minecraft",
+			},
+			// Bedrock keeps everything, including the other dimensions, in the one world directory
+			{
+				Name:      overworldMountName,
+				MountPath: "/run/minecraft/worlds/world",
+			},
+		},
+	}
+	// The server saves and stops when it's sent SIGTERM, so there's no graceful shutdown hook. It's run directly rather
+	// than under a shell so the signal reaches it. There are no probes either, as the server only speaks UDP, which
+	// Kubernetes can't probe.
+
+	gracePeriod := int64(bedrockTerminationGracePeriodSeconds)
+	var replicas int32 = 1
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            server.Name,
+			Namespace:       server.Namespace,
+			OwnerReferences: []metav1.OwnerReference{serverOwnerReference(server)},
+			Annotations: map[string]string{
+				serverBuildAnnotation: server.Spec.MinecraftVersion,
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels(server),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels(server),
+				},
+				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: &gracePeriod,
+					SecurityContext:               podSecurityContext(),
+					InitContainers:                []corev1.Container{installContainer},
+					Containers:                    []corev1.Container{mainContainer},
+					Volumes: []corev1.Volume{
+						{
+							Name: configVolumeMountName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: configMapNameForServer(server),
+									},
+								},
+							},
+						},
+						{
+							Name: serverZipVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						persistentVolume(bedrockWorkingDirVolumeName, persistence(server).ServerState),
+					},
+				},
+			},
+		},
+	}
+
+	var world *corev1.PersistentVolumeClaimVolumeSource
+	if server.Spec.World != nil {
+		world = server.Spec.World.Overworld
+	}
+	rs.Spec.Template.Spec.Volumes = append(rs.Spec.Template.Spec.Volumes, persistentVolume(overworldMountName, world))
+
+	// Put the security context on *everything*
+	for i := range rs.Spec.Template.Spec.InitContainers {
+		rs.Spec.Template.Spec.InitContainers[i].SecurityContext = SecurityContext()
+	}
+	for i := range rs.Spec.Template.Spec.Containers {
+		rs.Spec.Template.Spec.Containers[i].SecurityContext = SecurityContext()
+	}
+
+	return rs, nil
+}
@@ -0,0 +1,80 @@
+package minecraftserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func TestRsForServerTypeBedrock(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeBedrock
+	server.Spec.MinecraftVersion = "1.21.2.02"
+	server.Spec.World = &v1alpha1.WorldSpec{
+		Overworld: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "world"},
+	}
+
+	rs, err := rsForServerTypeBedrock(&server)
+	require.NoError(t, err)
+	assertOwnerReference(t, &server, &rs)
+	assert.Equal(t, "1.21.2.02", rs.Annotations[serverBuildAnnotation])
+
+	require.Len(t, rs.Spec.Template.Spec.InitContainers, 1)
+	install := rs.Spec.Template.Spec.InitContainers[0]
+	assert.Contains(t, install.Env, corev1.EnvVar{
+		Name:  "DOWNLOAD_URL",
+		Value: "https://www.minecraft.net/bedrockdedicatedserver/bin-linux/bedrock-server-1.21.2.02.zip",
+	})
+	assert.Contains(t, install.Env, corev1.EnvVar{Name: "EULA", Value: "Accepted"})
+	assert.NotContains(t, install.Args[2], "sha256sum")
+
+	require.Len(t, rs.Spec.Template.Spec.Containers, 1)
+	main := rs.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, defaultBedrockImage, main.Image)
+	assert.Equal(t, []string{"/run/minecraft/bedrock_server"}, main.Command)
+	assert.Contains(t, main.Ports, corev1.ContainerPort{Name: "minecraft", ContainerPort: 19132, Protocol: corev1.ProtocolUDP})
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{Name: overworldMountName, MountPath: "/run/minecraft/worlds/world"})
+	// Nothing here can talk to the server agent
+	assert.Nil(t, main.ReadinessProbe)
+	assert.Nil(t, main.Lifecycle)
+	assert.Equal(t, int64(bedrockTerminationGracePeriodSeconds), *rs.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	for _, e := range main.Env {
+		assert.NotEqual(t, "RCON_PASSWORD", e.Name)
+	}
+
+	assert.Contains(t, rs.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: overworldMountName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "world"},
+		},
+	})
+
+	server.Spec.Bedrock = &v1alpha1.BedrockSpec{
+		DownloadURL:       "https://example.com/bedrock-server.zip",
+		DownloadSHA256Sum: "abc123",
+		Image:             "example.com/bedrock-runtime",
+	}
+	rs, err = rsForServerTypeBedrock(&server)
+	require.NoError(t, err)
+	install = rs.Spec.Template.Spec.InitContainers[0]
+	assert.Contains(t, install.Env, corev1.EnvVar{Name: "DOWNLOAD_URL", Value: "https://example.com/bedrock-server.zip"})
+	assert.Contains(t, install.Env, corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "abc123"})
+	assert.Contains(t, install.Args[2], "sha256sum -c -")
+	// Only minecraft.net needs to think it's talking to a browser
+	assert.NotContains(t, install.Args[2], "Mozilla")
+	assert.Equal(t, "example.com/bedrock-runtime", rs.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestServiceForServerTypeBedrock(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeBedrock
+
+	service := serviceForServer(&server)
+	require.Len(t, service.Spec.Ports, 1)
+	assert.Equal(t, int32(19132), service.Spec.Ports[0].Port)
+	assert.Equal(t, corev1.ProtocolUDP, service.Spec.Ports[0].Protocol)
+}
@@ -0,0 +1,236 @@
+package minecraftserver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/fabricmeta"
+)
+
+// fabricVersions picks the loader and installer versions to use. Versions set in the spec win, then the pinned build,
+// which is recorded as "<loader>/<installer>", and otherwise the latest stable versions.
+func fabricVersions(ctx context.Context, fabricMetaClient *fabricmeta.Client, server *v1alpha1.MinecraftServer, pinnedBuild string) (string, string, error) {
+	var spec v1alpha1.FabricSpec
+	if server.Spec.Fabric != nil {
+		spec = *server.Spec.Fabric
+	}
+	pinnedLoader, pinnedInstaller, _ := strings.Cut(pinnedBuild, "/")
+
+	loader := spec.LoaderVersion
+	if loader == "" {
+		loader = pinnedLoader
+	}
+	if loader == "" {
+		var err error
+		loader, err = fabricMetaClient.LatestLoaderVersion(ctx, server.Spec.MinecraftVersion)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	installer := spec.InstallerVersion
+	if installer == "" {
+		installer = pinnedInstaller
+	}
+	if installer == "" {
+		var err error
+		installer, err = fabricMetaClient.LatestInstallerVersion(ctx)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return loader, installer, nil
+}
+
+func rsForServerTypeFabric(ctx context.Context, fabricMetaClient *fabricmeta.Client, server *v1alpha1.MinecraftServer, pinnedBuild string) (appsv1.ReplicaSet, error) {
+	const launcherJarVolumeName = "fabric-launcher-jar"
+	const configVolumeMountName = "config"
+	const dataPacksMountName = "data-packs"
+	const modsMountName = "mods"
+
+	loader, installer, err := fabricVersions(ctx, fabricMetaClient, server, pinnedBuild)
+	if err != nil {
+		return appsv1.ReplicaSet{}, errors.Wrapf(err, "failed to find Fabric versions for Minecraft %s", server.Spec.MinecraftVersion)
+	}
+
+	// Fabric meta doesn't publish checksums for the launcher, as it builds it on demand
+	launcherDownloadContainer := plainDownloadContainer(
+		fabricMetaClient.ServerJarURL(server.Spec.MinecraftVersion, loader, installer),
+		"fabric-server-launch.jar",
+		launcherJarVolumeName)
+	copyConfigContainer := copyConfigContainer(server, configVolumeMountName, fabricWorkingDirVolumeName)
+
+	initContainers := []corev1.Container{launcherDownloadContainer, copyConfigContainer, serverAgentInstallContainer()}
+
+	if server.Spec.Fabric != nil {
+		for i, mod := range server.Spec.Fabric.Mods {
+			filename := "mod-" + strconv.Itoa(i) + ".jar"
+			if mod.SHA256Sum == "" {
+				initContainers = append(initContainers, plainDownloadContainer(mod.URL, filename, modsMountName))
+			} else {
+				initContainers = append(initContainers, downloadContainer(mod.URL, mod.SHA256Sum, filename, modsMountName))
+			}
+		}
+	}
+
+	jvm, err := jvmArgs(server)
+	if err != nil {
+		return appsv1.ReplicaSet{}, err
+	}
+	javaArgs := append([]string{"java"},
+		///////////////////////////////
+		// Flags here are flags to Java
+		///////////////////////////////
+		jvm...)
+	javaArgs = append(javaArgs,
+		"-jar",
+		"/usr/local/minecraft/fabric-server-launch.jar",
+		////////////////////////////////////////////////////////////
+		// Flags after this point are flags to Minecraft, and not Java
+		////////////////////////////////////////////////////////////
+		// The launcher passes these on to the vanilla server, which keeps the other dimensions inside the world
+		// directory.
+		"--universe=/var/minecraft",
+		// Disable the GUI, no need in a container
+		"--nogui")
+
+	mainJavaContainer := corev1.Container{
+		Name:  "minecraft",
+		Image: javaImage(server),
+		Args:  javaArgs,
+		// The launcher downloads the vanilla server and Fabric's libraries into its working directory when it starts,
+		// and the server writes things like the user cache and ban lists there too.
+		WorkingDir: "/run/minecraft",
+		Resources:  serverResources(server),
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "minecraft",
+				ContainerPort: minecraftPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			// This gives the server a writeable runtime directory, this is used as the working directory.
+			{
+				Name:      fabricWorkingDirVolumeName,
+				MountPath: "/run/minecraft",
+			},
+			// Fabric loads mods from the mods directory in its working directory.
+			{
+				Name:      modsMountName,
+				MountPath: "/run/minecraft/mods",
+			},
+			// This will mount the JAR to /usr/local/minecraft/fabric-server-launch.jar
+			{
+				Name:      launcherJarVolumeName,
+				MountPath: "/usr/local/minecraft",
+			},
+			{
+				Name:      overworldMountName,
+				MountPath: "/var/minecraft/world",
+			},
+			// Mount the datapacks at /var/minecraft/world/datapacks
+			{
+				Name:      dataPacksMountName,
+				MountPath: "/var/minecraft/world/datapacks",
+			},
+		},
+	}
+
+	addServerProbes(&mainJavaContainer, 10)
+	addGracefulShutdown(server, &mainJavaContainer)
+
+	var replicas int32 = 1
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            server.Name,
+			Namespace:       server.Namespace,
+			OwnerReferences: []metav1.OwnerReference{serverOwnerReference(server)},
+			Annotations: map[string]string{
+				serverBuildAnnotation: loader + "/" + installer,
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels(server),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels(server),
+				},
+				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: terminationGracePeriodSeconds(server),
+					SecurityContext:               podSecurityContext(),
+					Volumes: []corev1.Volume{
+						{
+							Name: configVolumeMountName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: configMapNameForServer(server),
+									},
+								},
+							},
+						},
+						{
+							Name: launcherJarVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						persistentVolume(fabricWorkingDirVolumeName, persistence(server).ServerState),
+						// Mods are downloaded fresh each time, so removing one from the spec removes it from the server
+						{
+							Name: modsMountName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						{
+							Name: dataPacksMountName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						serverAgentVolume(),
+					},
+				},
+			},
+		},
+	}
+
+	var world *corev1.PersistentVolumeClaimVolumeSource
+	if server.Spec.World != nil {
+		world = server.Spec.World.Overworld
+	}
+	rs.Spec.Template.Spec.Volumes = append(rs.Spec.Template.Spec.Volumes, persistentVolume(overworldMountName, world))
+
+	if server.Spec.VanillaTweaks != nil {
+		vtDownloadContainer, err := vanillaTweaksDatapackContainer(ctx, dataPacksMountName, server.Spec.MinecraftVersion, server.Spec.VanillaTweaks)
+		if err != nil {
+			return appsv1.ReplicaSet{}, err
+		}
+		initContainers = append(initContainers, vtDownloadContainer)
+	}
+
+	rs.Spec.Template.Spec.InitContainers = initContainers
+	rs.Spec.Template.Spec.Containers = append(rs.Spec.Template.Spec.Containers, mainJavaContainer)
+
+	// Put the security context on *everything*
+	for i := range rs.Spec.Template.Spec.InitContainers {
+		rs.Spec.Template.Spec.InitContainers[i].SecurityContext = SecurityContext()
+	}
+	for i := range rs.Spec.Template.Spec.Containers {
+		rs.Spec.Template.Spec.Containers[i].SecurityContext = SecurityContext()
+	}
+
+	return rs, nil
+}
@@ -0,0 +1,101 @@
+package minecraftserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/fabricmeta"
+)
+
+func fakeFabricMeta(t *testing.T) *fabricmeta.Client {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/versions/loader/1.19":
+			fmt.Fprint(w, `[{"loader": {"version": "0.16.0-beta.1", "stable": false}}, {"loader": {"version": "0.15.6", "stable": true}}]`)
+		case "/v2/versions/installer":
+			fmt.Fprint(w, `[{"version": "1.0.0", "stable": true}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(fake.Close)
+	return fabricmeta.NewClient(fake.URL)
+}
+
+func TestRsForServerTypeFabric(t *testing.T) {
+	fabricMetaClient := fakeFabricMeta(t)
+
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeFabric
+	server.Spec.Fabric = &v1alpha1.FabricSpec{
+		Mods: []v1alpha1.ModSpec{
+			{URL: "https://example.com/fabric-api.jar", SHA256Sum: "abc123"},
+			{URL: "https://example.com/lithium.jar"},
+		},
+	}
+
+	rs, err := rsForServerTypeFabric(context.Background(), fabricMetaClient, &server, "")
+	require.NoError(t, err)
+	assertOwnerReference(t, &server, &rs)
+	assert.Equal(t, "0.15.6/1.0.0", rs.Annotations[serverBuildAnnotation])
+
+	// There's no checksum for the launcher, so it's downloaded without one rather than with an empty one
+	download := rs.Spec.Template.Spec.InitContainers[0]
+	assert.Equal(t, "busybox", download.Image)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "DOWNLOAD_URL", Value: fabricMetaClient.BaseURL + "/v2/versions/loader/1.19/0.15.6/1.0.0/server/jar"},
+		{Name: "DOWNLOAD_TARGET", Value: "/download/fabric-server-launch.jar"},
+	}, download.Env)
+
+	initContainer := func(name string) *corev1.Container {
+		for i, c := range rs.Spec.Template.Spec.InitContainers {
+			if c.Name == name {
+				return &rs.Spec.Template.Spec.InitContainers[i]
+			}
+		}
+		return nil
+	}
+	mod := initContainer("download-mod-0-jar")
+	require.NotNil(t, mod)
+	assert.Contains(t, mod.Env, corev1.EnvVar{Name: "DOWNLOAD_URL", Value: "https://example.com/fabric-api.jar"})
+	assert.Contains(t, mod.Env, corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "abc123"})
+
+	unchecked := initContainer("download-mod-1-jar")
+	require.NotNil(t, unchecked)
+	assert.Equal(t, "busybox", unchecked.Image)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "DOWNLOAD_URL", Value: "https://example.com/lithium.jar"},
+		{Name: "DOWNLOAD_TARGET", Value: "/download/mod-1.jar"},
+	}, unchecked.Env)
+
+	require.Len(t, rs.Spec.Template.Spec.Containers, 1)
+	main := rs.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, main.Args, "/usr/local/minecraft/fabric-server-launch.jar")
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{Name: "mods", MountPath: "/run/minecraft/mods"})
+}
+
+func TestRsForServerTypeFabricVersions(t *testing.T) {
+	fabricMetaClient := fakeFabricMeta(t)
+
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeFabric
+
+	// A pinned build is kept, even though there are newer versions
+	rs, err := rsForServerTypeFabric(context.Background(), fabricMetaClient, &server, "0.14.0/0.11.0")
+	require.NoError(t, err)
+	assert.Equal(t, "0.14.0/0.11.0", rs.Annotations[serverBuildAnnotation])
+
+	// Versions in the spec win over the pinned build
+	server.Spec.Fabric = &v1alpha1.FabricSpec{LoaderVersion: "0.15.0"}
+	rs, err = rsForServerTypeFabric(context.Background(), fabricMetaClient, &server, "0.14.0/0.11.0")
+	require.NoError(t, err)
+	assert.Equal(t, "0.15.0/0.11.0", rs.Annotations[serverBuildAnnotation])
+}
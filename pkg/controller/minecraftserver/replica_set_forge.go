@@ -30,26 +30,53 @@ func forgeDownloadUrl(server *v1alpha1.MinecraftServer) (*url.URL, error) {
 	}, nil
 }
 
+// forgeInstall is where to get the installer and modpack for a Forge or NeoForge server. Both are set up the same way
+// from there.
+type forgeInstall struct {
+	// build is recorded as the build of the server software
+	build        string
+	installerURL string
+	// installerSHA256 is optional, as NeoForge doesn't publish one. Without it the download isn't checked.
+	installerSHA256 string
+	// The modpack is optional, and is extracted into the working directory after the installer has run. Its checksum
+	// is optional too.
+	modpackURL    string
+	modpackSHA256 string
+}
+
 func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer) (appsv1.ReplicaSet, error) {
+	url, err := forgeDownloadUrl(server)
+	if err != nil {
+		return appsv1.ReplicaSet{}, err
+	}
+	return rsForForgeInstall(ctx, server, forgeInstall{
+		build:           server.Spec.Forge.ForgeVersion,
+		installerURL:    url.String(),
+		installerSHA256: server.Spec.Forge.ForgeInstallerSHA256Sum,
+		modpackURL:      server.Spec.Forge.ModpackZipURL,
+		modpackSHA256:   server.Spec.Forge.ModpackZipSHA256Sum,
+	})
+}
+
+// rsForForgeInstall generates the ReplicaSet for a server that's installed with a Forge-style installer, which sets up
+// the server's working directory and a run.sh script to start it with.
+func rsForForgeInstall(ctx context.Context, server *v1alpha1.MinecraftServer, install forgeInstall) (appsv1.ReplicaSet, error) {
 	const forgeInstallerVolumeName = "forge-installer-jar"
 	const installerTmp = "installer-tmp"
 	const modpackZipVolumeName = "modpack-zip-volume"
-	const forgeWorkingDirVolumeName = "forge-workingdir"
 	const configVolumeMountName = "config"
-	const worldMountName = "world-overworld"
 	const dataPacksMountName = "data-packs"
 
-	url, err := forgeDownloadUrl(server)
-	if err != nil {
-		return appsv1.ReplicaSet{}, err
+	var forgeDownloadContainer corev1.Container
+	if install.installerSHA256 == "" {
+		forgeDownloadContainer = plainDownloadContainer(install.installerURL, "forge-installer.jar", forgeInstallerVolumeName)
+	} else {
+		forgeDownloadContainer = downloadContainer(install.installerURL, install.installerSHA256, "forge-installer.jar", forgeInstallerVolumeName)
 	}
-
-	forgeDownloadContainer := downloadContainer(url.String(), server.Spec.Forge.ForgeInstallerSHA256Sum, "forge-installer.jar", forgeInstallerVolumeName)
-	copyConfigContainer := copyConfigContainer(configVolumeMountName, forgeWorkingDirVolumeName)
+	copyConfigContainer := copyConfigContainer(server, configVolumeMountName, forgeWorkingDirVolumeName)
 	forgeInstallerContainer := corev1.Container{
-		Name: "forge-installer",
-		// TODO Configure Java Version
-		Image: "eclipse-temurin:17",
+		Name:  "forge-installer",
+		Image: javaImage(server),
 		Args: []string{
 			"java",
 			///////////////////////////////
@@ -63,7 +90,7 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 			// Disable the GUI, no need in a container
 			"--installServer=/run/minecraft"},
 		WorkingDir: "/usr/local/minecraft",
-		// TODO Make resources configurable
+		// The installer doesn't need anything like as much as the server itself
 		Resources: corev1.ResourceRequirements{
 			Limits: corev1.ResourceList{
 				corev1.ResourceMemory: resource.MustParse("6Gi"),
@@ -89,17 +116,19 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 			},
 		},
 	}
-	modpackDownloadContainer := downloadContainer(
-		server.Spec.Forge.ModpackZipURL,
-		server.Spec.Forge.ModpackZipSHA256Sum,
-		"modpack.zip",
-		modpackZipVolumeName)
+	var modpackDownloadContainer corev1.Container
+	if install.modpackSHA256 == "" {
+		modpackDownloadContainer = plainDownloadContainer(install.modpackURL, "modpack.zip", modpackZipVolumeName)
+	} else {
+		modpackDownloadContainer = downloadContainer(install.modpackURL, install.modpackSHA256, "modpack.zip", modpackZipVolumeName)
+	}
 	modpackUnzipContainer := corev1.Container{
 		Name: "modpack-unzip",
 		// TODO Configure Java Version
 		Image: "busybox",
 		Args: []string{
-			"unzip", "/usr/local/modpack/modpack.zip", "-d/run/minecraft"},
+			// Overwrite anything left from last time, if the working directory is persisted
+			"unzip", "-o", "/usr/local/modpack/modpack.zip", "-d/run/minecraft"},
 		// TODO Make resources configurable
 		Resources: corev1.ResourceRequirements{
 			Limits: corev1.ResourceList{
@@ -123,17 +152,15 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 		},
 	}
 
-	initContainers := []corev1.Container{
-		forgeDownloadContainer,
-		forgeInstallerContainer,
-		modpackDownloadContainer,
-		modpackUnzipContainer,
-		copyConfigContainer}
+	initContainers := []corev1.Container{forgeDownloadContainer, forgeInstallerContainer}
+	if install.modpackURL != "" {
+		initContainers = append(initContainers, modpackDownloadContainer, modpackUnzipContainer)
+	}
+	initContainers = append(initContainers, copyConfigContainer, serverAgentInstallContainer())
 
 	mainJavaContainer := corev1.Container{
-		Name: "minecraft",
-		// TODO Configure Java Version
-		Image: "eclipse-temurin:17",
+		Name:  "minecraft",
+		Image: javaImage(server),
 		Args: []string{
 			"sh",
 			"run.sh",
@@ -148,17 +175,7 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 		// Paper expects to be able to write all kinds of stuff to it's working directory, so we give it a dedicated
 		// scratch dir for it's use under /run/minecraft.
 		WorkingDir: "/run/minecraft",
-		// TODO Make resources configurable
-		Resources: corev1.ResourceRequirements{
-			Limits: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse("12Gi"),
-				// No CPU limit to avoid CPU throttling
-			},
-			Requests: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse("8Gi"),
-				corev1.ResourceCPU:    resource.MustParse("2"),
-			},
-		},
+		Resources:  serverResources(server),
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "minecraft",
@@ -174,7 +191,7 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 			},
 			// Mount the various world directories under /var/minecraft
 			{
-				Name:      worldMountName,
+				Name:      overworldMountName,
 				MountPath: "/run/minecraft/world",
 			},
 			{
@@ -184,12 +201,19 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 		},
 	}
 
+	// Modpacks can take a very long time to load
+	addServerProbes(&mainJavaContainer, 30)
+	addGracefulShutdown(server, &mainJavaContainer)
+
 	var replicas int32 = 1
 	rs := appsv1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            server.Name,
 			Namespace:       server.Namespace,
 			OwnerReferences: []metav1.OwnerReference{serverOwnerReference(server)},
+			Annotations: map[string]string{
+				serverBuildAnnotation: install.build,
+			},
 		},
 		Spec: appsv1.ReplicaSetSpec{
 			Replicas: &replicas,
@@ -201,6 +225,8 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 					Labels: podLabels(server),
 				},
 				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: terminationGracePeriodSeconds(server),
+					SecurityContext:               podSecurityContext(),
 					Volumes: []corev1.Volume{
 						{
 							Name: configVolumeMountName,
@@ -218,12 +244,7 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 								EmptyDir: &corev1.EmptyDirVolumeSource{},
 							},
 						},
-						{
-							Name: forgeWorkingDirVolumeName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
+						persistentVolume(forgeWorkingDirVolumeName, persistence(server).ServerState),
 						{
 							Name: dataPacksMountName,
 							VolumeSource: corev1.VolumeSource{
@@ -242,31 +263,18 @@ func rsForServerTypeForge(ctx context.Context, server *v1alpha1.MinecraftServer)
 								EmptyDir: &corev1.EmptyDirVolumeSource{},
 							},
 						},
+						serverAgentVolume(),
 					},
 				},
 			},
 		},
 	}
 
+	var world *corev1.PersistentVolumeClaimVolumeSource
 	if server.Spec.World != nil {
-		// Persistent world, so mount so PVCs
-		rs.Spec.Template.Spec.Volumes = append(rs.Spec.Template.Spec.Volumes,
-			corev1.Volume{
-				Name: worldMountName,
-				VolumeSource: corev1.VolumeSource{
-					PersistentVolumeClaim: server.Spec.World.Overworld,
-				},
-			})
-	} else {
-		// No world to persist, so mount EmptyDir volumes.
-		rs.Spec.Template.Spec.Volumes = append(rs.Spec.Template.Spec.Volumes,
-			corev1.Volume{
-				Name: worldMountName,
-				VolumeSource: corev1.VolumeSource{
-					EmptyDir: &corev1.EmptyDirVolumeSource{},
-				},
-			})
+		world = server.Spec.World.Overworld
 	}
+	rs.Spec.Template.Spec.Volumes = append(rs.Spec.Template.Spec.Volumes, persistentVolume(overworldMountName, world))
 
 	if server.Spec.VanillaTweaks != nil {
 		vtDownloadContainer, err := vanillaTweaksDatapackContainer(ctx, dataPacksMountName, server.Spec.MinecraftVersion, server.Spec.VanillaTweaks)
@@ -1,10 +1,12 @@
 package minecraftserver
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
 )
@@ -41,3 +43,22 @@ func TestForgeDownloadUrl(t *testing.T) {
 		)
 	})
 }
+
+func TestRsForServerTypeForgePersistence(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeForge
+	server.Spec.Forge = &v1alpha1.ForgeSpec{ForgeVersion: "43.1.1"}
+	server.Spec.Persistence = &v1alpha1.PersistenceSpec{
+		ServerState: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "state"},
+	}
+
+	rs, err := rsForServerTypeForge(context.Background(), &server)
+	require.NoError(t, err)
+	assert.Contains(t, rs.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: forgeWorkingDirVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "state"},
+		},
+	})
+	assert.Equal(t, int64(1000), *rs.Spec.Template.Spec.SecurityContext.FSGroup)
+}
@@ -0,0 +1,40 @@
+package minecraftserver
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/neoforge"
+)
+
+// rsForServerTypeNeoForge generates the ReplicaSet for a NeoForge server. The version set in the spec wins, then the
+// pinned build, and otherwise the latest version for the Minecraft version.
+func rsForServerTypeNeoForge(ctx context.Context, neoForgeClient *neoforge.Client, server *v1alpha1.MinecraftServer, pinnedBuild string) (appsv1.ReplicaSet, error) {
+	var spec v1alpha1.NeoForgeSpec
+	if server.Spec.NeoForge != nil {
+		spec = *server.Spec.NeoForge
+	}
+
+	neoForgeVersion := spec.NeoForgeVersion
+	if neoForgeVersion == "" {
+		neoForgeVersion = pinnedBuild
+	}
+	if neoForgeVersion == "" {
+		var err error
+		neoForgeVersion, err = neoForgeClient.LatestVersion(ctx, server.Spec.MinecraftVersion)
+		if err != nil {
+			return appsv1.ReplicaSet{}, errors.Wrapf(err, "failed to find NeoForge version for Minecraft %s", server.Spec.MinecraftVersion)
+		}
+	}
+
+	return rsForForgeInstall(ctx, server, forgeInstall{
+		build:           neoForgeVersion,
+		installerURL:    neoForgeClient.InstallerURL(neoForgeVersion),
+		installerSHA256: spec.InstallerSHA256Sum,
+		modpackURL:      spec.ModpackZipURL,
+		modpackSHA256:   spec.ModpackZipSHA256Sum,
+	})
+}
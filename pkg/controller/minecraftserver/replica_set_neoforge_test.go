@@ -0,0 +1,106 @@
+package minecraftserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/neoforge"
+)
+
+func TestRsForServerTypeNeoForge(t *testing.T) {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/net/neoforged/neoforge/maven-metadata.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<metadata><versioning><versions>
+  <version>20.4.190</version>
+  <version>20.4.237</version>
+  <version>21.0.10-beta</version>
+</versions></versioning></metadata>`)
+	}))
+	defer fake.Close()
+	sources := &Sources{NeoForge: neoforge.NewClient(fake.URL)}
+
+	initContainer := func(rs corev1.PodSpec, name string) *corev1.Container {
+		for i, c := range rs.InitContainers {
+			if c.Name == name {
+				return &rs.InitContainers[i]
+			}
+		}
+		return nil
+	}
+	initContainerNames := func(rs corev1.PodSpec) []string {
+		var names []string
+		for _, c := range rs.InitContainers {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeNeoForge
+	server.Spec.MinecraftVersion = "1.20.4"
+
+	t.Run("latest version without a modpack", func(t *testing.T) {
+		rs, err := rsForServer(context.Background(), sources, &server, "")
+		require.NoError(t, err)
+		assert.Equal(t, "20.4.237", rs.Annotations[serverBuildAnnotation])
+		// There's no checksum to check the installer against, so it's downloaded without one
+		download := rs.Spec.Template.Spec.InitContainers[0]
+		assert.Equal(t, "busybox", download.Image)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "DOWNLOAD_URL", Value: fake.URL + "/net/neoforged/neoforge/20.4.237/neoforge-20.4.237-installer.jar"},
+			{Name: "DOWNLOAD_TARGET", Value: "/download/forge-installer.jar"},
+		}, download.Env)
+		assert.Contains(t, initContainerNames(rs.Spec.Template.Spec), "forge-installer")
+		assert.NotContains(t, initContainerNames(rs.Spec.Template.Spec), "modpack-unzip")
+		assert.Equal(t, []string{"sh", "run.sh", "--nogui"}, rs.Spec.Template.Spec.Containers[0].Args)
+	})
+
+	t.Run("pinned version", func(t *testing.T) {
+		rs, err := rsForServer(context.Background(), sources, &server, "20.4.190")
+		require.NoError(t, err)
+		assert.Equal(t, "20.4.190", rs.Annotations[serverBuildAnnotation])
+	})
+
+	t.Run("version and modpack from the spec", func(t *testing.T) {
+		server := server
+		server.Spec.NeoForge = &v1alpha1.NeoForgeSpec{
+			NeoForgeVersion: "20.4.80-beta",
+			ModpackZipURL:   "https://example.com/modpack.zip",
+		}
+		rs, err := rsForServer(context.Background(), sources, &server, "20.4.190")
+		require.NoError(t, err)
+		assert.Equal(t, "20.4.80-beta", rs.Annotations[serverBuildAnnotation])
+		assert.Contains(t, initContainerNames(rs.Spec.Template.Spec), "modpack-unzip")
+		modpack := initContainer(rs.Spec.Template.Spec, "download-modpack-zip")
+		require.NotNil(t, modpack)
+		assert.Equal(t, "busybox", modpack.Image)
+	})
+
+	t.Run("checksums from the spec", func(t *testing.T) {
+		server := server
+		server.Spec.NeoForge = &v1alpha1.NeoForgeSpec{
+			InstallerSHA256Sum:  "abc123",
+			ModpackZipURL:       "https://example.com/modpack.zip",
+			ModpackZipSHA256Sum: "def456",
+		}
+		rs, err := rsForServer(context.Background(), sources, &server, "")
+		require.NoError(t, err)
+		installer := initContainer(rs.Spec.Template.Spec, "download-forge-installer-jar")
+		require.NotNil(t, installer)
+		assert.Contains(t, installer.Env, corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "abc123"})
+		modpack := initContainer(rs.Spec.Template.Spec, "download-modpack-zip")
+		require.NotNil(t, modpack)
+		assert.Contains(t, modpack.Env, corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "def456"})
+	})
+}
@@ -0,0 +1,158 @@
+package minecraftserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/bibliothek"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/purpur"
+)
+
+func TestRsForServerTypePaperForks(t *testing.T) {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/projects/paper/versions/1.19/builds":
+			fmt.Fprint(w, `{"builds": [
+  {"build": 10, "channel": "default", "downloads": {"application": {"name": "paper-1.19-10.jar", "sha256": "aaa"}}},
+  {"build": 11, "channel": "experimental", "downloads": {"application": {"name": "paper-1.19-11.jar", "sha256": "bbb"}}}
+]}`)
+		case "/v2/projects/paper/versions/1.19/builds/9":
+			fmt.Fprint(w, `{"build": 9, "channel": "default", "downloads": {"application": {"name": "paper-1.19-9.jar", "sha256": "ccc"}}}`)
+		case "/v2/projects/folia/versions/1.19/builds":
+			fmt.Fprint(w, `{"builds": [
+  {"build": 3, "channel": "experimental", "downloads": {"application": {"name": "folia-1.19-3.jar", "sha256": "ddd"}}}
+]}`)
+		case "/v2/purpur/1.19":
+			fmt.Fprint(w, `{"builds": {"latest": "1700", "all": ["1699", "1700"]}}`)
+		case "/v2/purpur/1.19/1700":
+			fmt.Fprint(w, `{"version": "1.19", "build": "1700", "result": "SUCCESS", "md5": "eee"}`)
+		case "/v2/purpur/1.19/1699":
+			fmt.Fprint(w, `{"version": "1.19", "build": "1699", "result": "SUCCESS", "md5": "fff"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer fake.Close()
+	sources := &Sources{
+		Paper:  bibliothek.NewClient(fake.URL),
+		Purpur: purpur.NewClient(fake.URL),
+	}
+
+	tests := []struct {
+		name        string
+		serverType  v1alpha1.ServerType
+		paper       *v1alpha1.PaperSpec
+		pinnedBuild string
+		build       string
+		url         string
+		checksum    corev1.EnvVar
+	}{
+		{
+			name:       "paper uses the default channel",
+			serverType: v1alpha1.ServerTypePaper,
+			build:      "10",
+			url:        fake.URL + "/v2/projects/paper/versions/1.19/builds/10/downloads/paper-1.19-10.jar",
+			checksum:   corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "aaa"},
+		},
+		{
+			name:       "paper on the experimental channel",
+			serverType: v1alpha1.ServerTypePaper,
+			paper:      &v1alpha1.PaperSpec{Channel: v1alpha1.PaperChannelExperimental},
+			build:      "11",
+			url:        fake.URL + "/v2/projects/paper/versions/1.19/builds/11/downloads/paper-1.19-11.jar",
+			checksum:   corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "bbb"},
+		},
+		{
+			name:        "paper with pinned build",
+			serverType:  v1alpha1.ServerTypePaper,
+			pinnedBuild: "9",
+			build:       "9",
+			url:         fake.URL + "/v2/projects/paper/versions/1.19/builds/9/downloads/paper-1.19-9.jar",
+			checksum:    corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "ccc"},
+		},
+		{
+			name:       "folia uses the experimental channel",
+			serverType: v1alpha1.ServerTypeFolia,
+			build:      "3",
+			url:        fake.URL + "/v2/projects/folia/versions/1.19/builds/3/downloads/folia-1.19-3.jar",
+			checksum:   corev1.EnvVar{Name: "DOWNLOAD_SHA256", Value: "ddd"},
+		},
+		{
+			name:       "purpur",
+			serverType: v1alpha1.ServerTypePurpur,
+			build:      "1700",
+			url:        fake.URL + "/v2/purpur/1.19/1700/download",
+			checksum:   corev1.EnvVar{Name: "DOWNLOAD_CHECKSUM", Value: "eee"},
+		},
+		{
+			name:        "purpur with pinned build",
+			serverType:  v1alpha1.ServerTypePurpur,
+			pinnedBuild: "1699",
+			build:       "1699",
+			url:         fake.URL + "/v2/purpur/1.19/1699/download",
+			checksum:    corev1.EnvVar{Name: "DOWNLOAD_CHECKSUM", Value: "fff"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := generateTestServer()
+			server.Spec.Type = tt.serverType
+			server.Spec.Paper = tt.paper
+
+			rs, err := rsForServer(context.Background(), sources, &server, tt.pinnedBuild)
+			require.NoError(t, err)
+			assert.Equal(t, tt.build, rs.Annotations[serverBuildAnnotation])
+			assert.Equal(t, string(tt.serverType), rs.Annotations[serverTypeAnnotation])
+			download := rs.Spec.Template.Spec.InitContainers[0]
+			assert.Contains(t, download.Env, corev1.EnvVar{Name: "DOWNLOAD_URL", Value: tt.url})
+			assert.Contains(t, download.Env, tt.checksum)
+			assert.Contains(t, rs.Spec.Template.Spec.Containers[0].Args, "--plugins=/usr/local/minecraft/plugins")
+		})
+	}
+}
+
+func TestRsForServerTypePaperPlugins(t *testing.T) {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"builds": [
+  {"build": 10, "channel": "default", "downloads": {"application": {"name": "paper-1.19-10.jar", "sha256": "aaa"}}}
+]}`)
+	}))
+	defer fake.Close()
+	sources := &Sources{Paper: bibliothek.NewClient(fake.URL)}
+
+	initContainerNames := func(rs appsv1.ReplicaSet) []string {
+		var names []string
+		for _, c := range rs.Spec.Template.Spec.InitContainers {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	// Operator-managed plugins are always removed, in case the plugins directory is persisted and they've since been
+	// disabled.
+	server := generateTestServer()
+	rs, err := rsForServer(context.Background(), sources, &server, "")
+	require.NoError(t, err)
+	assert.Contains(t, initContainerNames(rs), "remove-managed-plugins")
+	assert.NotContains(t, initContainerNames(rs), "install-dynmap")
+
+	server.Spec.Dynmap = &v1alpha1.DynmapSpec{Enabled: true}
+	rs, err = rsForServer(context.Background(), sources, &server, "")
+	require.NoError(t, err)
+	names := initContainerNames(rs)
+	require.Contains(t, names, "install-dynmap")
+	for i, name := range names {
+		if name == "install-dynmap" {
+			assert.Equal(t, "remove-managed-plugins", names[i-1])
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package minecraftserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func TestPodTemplateHash(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.VanillaTweaks = &v1alpha1.VanillaTweaks{
+		Datapacks: []v1alpha1.VanillaTweaksDatapack{{Name: "afk display", Category: "survival"}},
+	}
+	template := func(vanillaTweaksURL, image string) *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: vanillaTweaksContainerName, Args: []string{vanillaTweaksURL}}},
+				Containers:     []corev1.Container{{Name: "minecraft", Image: image}},
+			},
+		}
+	}
+
+	original, err := podTemplateHash(&server, template("https://example.com/a.zip", "eclipse-temurin:17"))
+	require.NoError(t, err)
+
+	t.Run("stable", func(t *testing.T) {
+		h, err := podTemplateHash(&server, template("https://example.com/a.zip", "eclipse-temurin:17"))
+		require.NoError(t, err)
+		assert.Equal(t, original, h)
+	})
+	t.Run("ignores VanillaTweaks download link", func(t *testing.T) {
+		h, err := podTemplateHash(&server, template("https://example.com/b.zip", "eclipse-temurin:17"))
+		require.NoError(t, err)
+		assert.Equal(t, original, h)
+	})
+	t.Run("changed datapacks", func(t *testing.T) {
+		changed := server.DeepCopy()
+		changed.Spec.VanillaTweaks.Datapacks[0].Name = "real time clock"
+		h, err := podTemplateHash(changed, template("https://example.com/a.zip", "eclipse-temurin:17"))
+		require.NoError(t, err)
+		assert.NotEqual(t, original, h)
+	})
+	t.Run("changed template", func(t *testing.T) {
+		h, err := podTemplateHash(&server, template("https://example.com/a.zip", "eclipse-temurin:21"))
+		require.NoError(t, err)
+		assert.NotEqual(t, original, h)
+	})
+}
+
+func TestConfigHash(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.MOTD = "Hello"
+	maxPlayers := int32(10)
+	server.Spec.MaxPlayers = &maxPlayers
+
+	original, err := configHash(&server)
+	require.NoError(t, err)
+
+	// Map iteration order is random, so check a few times to be sure it doesn't leak into the hash.
+	for i := 0; i < 10; i++ {
+		h, err := configHash(&server)
+		require.NoError(t, err)
+		assert.Equal(t, original, h)
+	}
+
+	server.Spec.MOTD = "Goodbye"
+	h, err := configHash(&server)
+	require.NoError(t, err)
+	assert.NotEqual(t, original, h)
+}
+
+func TestPinnedBuildFor(t *testing.T) {
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypePaper
+	server.Spec.MinecraftVersion = "1.19"
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+	}{
+		{
+			name: "same type and version",
+			annotations: map[string]string{
+				serverTypeAnnotation:       "Paper",
+				minecraftVersionAnnotation: "1.19",
+				serverBuildAnnotation:      "10",
+			},
+			expected: "10",
+		},
+		{
+			name: "different version",
+			annotations: map[string]string{
+				serverTypeAnnotation:       "Paper",
+				minecraftVersionAnnotation: "1.18.2",
+				serverBuildAnnotation:      "10",
+			},
+		},
+		{
+			name: "different type",
+			annotations: map[string]string{
+				serverTypeAnnotation:       "Purpur",
+				minecraftVersionAnnotation: "1.19",
+				serverBuildAnnotation:      "1700",
+			},
+		},
+		{
+			name: "no type recorded",
+			annotations: map[string]string{
+				minecraftVersionAnnotation: "1.19",
+				serverBuildAnnotation:      "10",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pinnedBuildFor(&server, tt.annotations))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package minecraftserver
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/mojang"
+)
+
+func rsForServerTypeVanilla(ctx context.Context, mojangClient *mojang.Client, server *v1alpha1.MinecraftServer) (appsv1.ReplicaSet, error) {
+	const serverJarVolumeName = "server-jar"
+	const configVolumeMountName = "config"
+	const dataPacksMountName = "data-packs"
+
+	download, err := mojangClient.ServerDownload(ctx, server.Spec.MinecraftVersion)
+	if err != nil {
+		return appsv1.ReplicaSet{}, errors.Wrapf(err, "failed to find server download for Minecraft %s", server.Spec.MinecraftVersion)
+	}
+
+	serverDownloadContainer := checksumDownloadContainer(download.URL, "sha1", download.SHA1, "server.jar", serverJarVolumeName)
+	copyConfigContainer := copyConfigContainer(server, configVolumeMountName, vanillaWorkingDirVolumeName)
+
+	initContainers := []corev1.Container{serverDownloadContainer, copyConfigContainer, serverAgentInstallContainer()}
+
+	jvm, err := jvmArgs(server)
+	if err != nil {
+		return appsv1.ReplicaSet{}, err
+	}
+	javaArgs := append([]string{"java"},
+		///////////////////////////////
+		// Flags here are flags to Java
+		///////////////////////////////
+		jvm...)
+	javaArgs = append(javaArgs,
+		"-jar",
+		"/usr/local/minecraft/server.jar",
+		////////////////////////////////////////////////////////////
+		// Flags after this point are flags to Minecraft, and not Java
+		////////////////////////////////////////////////////////////
+		// Look for the world in /var/minecraft. The vanilla server keeps the other dimensions inside the world directory.
+		"--universe=/var/minecraft",
+		// Disable the GUI, no need in a container
+		"--nogui")
+
+	mainJavaContainer := corev1.Container{
+		Name:  "minecraft",
+		Image: javaImage(server),
+		Args:  javaArgs,
+		// The server writes things like the user cache and ban lists to its working directory, so we give it a
+		// dedicated scratch dir for its use under /run/minecraft.
+		WorkingDir: "/run/minecraft",
+		Resources:  serverResources(server),
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "minecraft",
+				ContainerPort: minecraftPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			// This gives the server a writeable runtime directory, this is used as the working directory.
+			{
+				Name:      vanillaWorkingDirVolumeName,
+				MountPath: "/run/minecraft",
+			},
+			// This will mount the JAR to /usr/local/minecraft/server.jar
+			{
+				Name:      serverJarVolumeName,
+				MountPath: "/usr/local/minecraft",
+			},
+			{
+				Name:      overworldMountName,
+				MountPath: "/var/minecraft/world",
+			},
+			// Mount the datapacks at /var/minecraft/world/datapacks
+			{
+				Name:      dataPacksMountName,
+				MountPath: "/var/minecraft/world/datapacks",
+			},
+		},
+	}
+
+	addServerProbes(&mainJavaContainer, 10)
+	addGracefulShutdown(server, &mainJavaContainer)
+
+	var replicas int32 = 1
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            server.Name,
+			Namespace:       server.Namespace,
+			OwnerReferences: []metav1.OwnerReference{serverOwnerReference(server)},
+			Annotations: map[string]string{
+				serverBuildAnnotation: server.Spec.MinecraftVersion,
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels(server),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels(server),
+				},
+				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: terminationGracePeriodSeconds(server),
+					SecurityContext:               podSecurityContext(),
+					Volumes: []corev1.Volume{
+						{
+							Name: configVolumeMountName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: configMapNameForServer(server),
+									},
+								},
+							},
+						},
+						{
+							Name: serverJarVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						persistentVolume(vanillaWorkingDirVolumeName, persistence(server).ServerState),
+						{
+							Name: dataPacksMountName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						serverAgentVolume(),
+					},
+				},
+			},
+		},
+	}
+
+	var world *corev1.PersistentVolumeClaimVolumeSource
+	if server.Spec.World != nil {
+		world = server.Spec.World.Overworld
+	}
+	rs.Spec.Template.Spec.Volumes = append(rs.Spec.Template.Spec.Volumes, persistentVolume(overworldMountName, world))
+
+	if server.Spec.VanillaTweaks != nil {
+		vtDownloadContainer, err := vanillaTweaksDatapackContainer(ctx, dataPacksMountName, server.Spec.MinecraftVersion, server.Spec.VanillaTweaks)
+		if err != nil {
+			return appsv1.ReplicaSet{}, err
+		}
+		initContainers = append(initContainers, vtDownloadContainer)
+	}
+
+	rs.Spec.Template.Spec.InitContainers = initContainers
+	rs.Spec.Template.Spec.Containers = append(rs.Spec.Template.Spec.Containers, mainJavaContainer)
+
+	// Put the security context on *everything*
+	for i := range rs.Spec.Template.Spec.InitContainers {
+		rs.Spec.Template.Spec.InitContainers[i].SecurityContext = SecurityContext()
+	}
+	for i := range rs.Spec.Template.Spec.Containers {
+		rs.Spec.Template.Spec.Containers[i].SecurityContext = SecurityContext()
+	}
+
+	return rs, nil
+}
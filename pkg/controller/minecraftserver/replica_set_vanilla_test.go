@@ -0,0 +1,57 @@
+package minecraftserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/mojang"
+)
+
+func TestRsForServerTypeVanilla(t *testing.T) {
+	var fake *httptest.Server
+	fake = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mc/game/version_manifest_v2.json":
+			fmt.Fprintf(w, `{"versions": [{"id": "1.19", "url": "%s/1.19.json"}]}`, fake.URL)
+		case "/1.19.json":
+			fmt.Fprint(w, `{"downloads": {"server": {"sha1": "abc123", "url": "https://example.com/server.jar"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer fake.Close()
+
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeVanilla
+	server.Spec.World = &v1alpha1.WorldSpec{
+		Overworld: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "world"},
+	}
+
+	rs, err := rsForServerTypeVanilla(context.Background(), mojang.NewClient(fake.URL), &server)
+	require.NoError(t, err)
+	assertOwnerReference(t, &server, &rs)
+
+	download := rs.Spec.Template.Spec.InitContainers[0]
+	assert.Contains(t, download.Env, corev1.EnvVar{Name: "DOWNLOAD_URL", Value: "https://example.com/server.jar"})
+	assert.Contains(t, download.Env, corev1.EnvVar{Name: "DOWNLOAD_CHECKSUM", Value: "abc123"})
+
+	require.Len(t, rs.Spec.Template.Spec.Containers, 1)
+	assert.Contains(t, rs.Spec.Template.Spec.Containers[0].Args, "--universe=/var/minecraft")
+	assert.Contains(t, rs.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: overworldMountName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "world"},
+		},
+	})
+	for _, v := range rs.Spec.Template.Spec.Volumes {
+		assert.NotEqual(t, netherMountName, v.Name)
+	}
+}
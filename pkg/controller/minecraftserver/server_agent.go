@@ -0,0 +1,68 @@
+package minecraftserver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// serverAgentImage is the image containing the server agent, see cmd/server-agent.
+const serverAgentImage = "ghcr.io/jameslaverack/kubernetes-minecraft-operator-server-agent:edge"
+
+// serverAgentDir is where the server agent binary is installed to in the Minecraft container.
+const serverAgentDir = "/opt/server-agent"
+
+const serverAgentVolumeName = "server-agent"
+
+// serverAgentInstallContainer copies the server agent binary into a shared volume, so that the Minecraft container can
+// run it for probes.
+func serverAgentInstallContainer() corev1.Container {
+	return corev1.Container{
+		Name:            "install-server-agent",
+		Image:           serverAgentImage,
+		ImagePullPolicy: corev1.PullAlways,
+		Args:            []string{"install", serverAgentDir},
+		VolumeMounts:    []corev1.VolumeMount{serverAgentVolumeMount()},
+	}
+}
+
+func serverAgentVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: serverAgentVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func serverAgentVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      serverAgentVolumeName,
+		MountPath: serverAgentDir,
+	}
+}
+
+// serverAgentProbe is a probe that uses the server agent to do a Server List Ping against the Minecraft server. This is
+// a much better check than just seeing if the port is open, as the port is opened well before the server is ready for
+// players, and stays open if the server is wedged.
+func serverAgentProbe(periodSeconds, failureThreshold int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{serverAgentDir + "/server-agent", "probe", "--timeout=5s"},
+			},
+		},
+		TimeoutSeconds:   10,
+		PeriodSeconds:    periodSeconds,
+		FailureThreshold: failureThreshold,
+	}
+}
+
+// addServerProbes sets up probes on the Minecraft container. The startup probe allows for the given amount of time in
+// minutes for the server to start, as generating the spawn area for a new world (or loading a large modpack) is slow.
+func addServerProbes(container *corev1.Container, startupMinutes int32) {
+	container.StartupProbe = serverAgentProbe(10, startupMinutes*6)
+	container.ReadinessProbe = serverAgentProbe(10, 3)
+	// Be a bit more forgiving before restarting. The server will stop responding for a while if it's overloaded, and
+	// restarting it then won't help anyone.
+	container.LivenessProbe = serverAgentProbe(30, 4)
+	container.VolumeMounts = append(container.VolumeMounts, serverAgentVolumeMount())
+}
@@ -107,6 +107,12 @@ func serviceForServer(server *minecraftv1alpha1.MinecraftServer) corev1.Service
 		},
 	}
 
+	// Bedrock clients connect over UDP, on a different port
+	if server.Spec.Type == minecraftv1alpha1.ServerTypeBedrock {
+		service.Spec.Ports[0].Port = bedrockPort
+		service.Spec.Ports[0].Protocol = corev1.ProtocolUDP
+	}
+
 	if server.Spec.Service.MinecraftNodePort != nil && *server.Spec.Service.MinecraftNodePort > 0 {
 		service.Spec.Ports[0].NodePort = *server.Spec.Service.MinecraftNodePort
 	}
@@ -0,0 +1,67 @@
+package minecraftserver
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+const defaultShutdownCountdownSeconds = 30
+const defaultShutdownStopTimeoutSeconds = 60
+const defaultShutdownMessage = "Server is restarting"
+
+// shutdownGracePeriodMarginSeconds is extra time we allow on top of the countdown and stop timeout, to cover the server
+// agent connecting to the server and Kubernetes being slow to react.
+const shutdownGracePeriodMarginSeconds = 10
+
+// shutdownSettings returns the countdown, stop timeout (both in seconds), and message to use when stopping the server,
+// filling in defaults for anything not set in the spec.
+func shutdownSettings(server *minecraftv1alpha1.MinecraftServer) (int32, int32, string) {
+	countdown := int32(defaultShutdownCountdownSeconds)
+	stopTimeout := int32(defaultShutdownStopTimeoutSeconds)
+	message := defaultShutdownMessage
+	if s := server.Spec.Shutdown; s != nil {
+		if s.CountdownSeconds != nil {
+			countdown = *s.CountdownSeconds
+		}
+		if s.StopTimeoutSeconds != nil {
+			stopTimeout = *s.StopTimeoutSeconds
+		}
+		if s.Message != "" {
+			message = s.Message
+		}
+	}
+	return countdown, stopTimeout, message
+}
+
+// addGracefulShutdown sets up a preStop hook on the Minecraft container that uses the server agent to warn players,
+// save the world, and stop the server over RCON. Without this the JVM just gets a SIGTERM, which disconnects everyone
+// without warning. Some server types (e.g., Forge) run the JVM under a shell script, which doesn't pass the signal on
+// at all.
+func addGracefulShutdown(server *minecraftv1alpha1.MinecraftServer, container *corev1.Container) {
+	countdown, stopTimeout, message := shutdownSettings(server)
+	container.Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					serverAgentDir + "/server-agent",
+					"shutdown",
+					"--countdown=" + strconv.Itoa(int(countdown)) + "s",
+					"--stop-timeout=" + strconv.Itoa(int(stopTimeout)) + "s",
+					"--message=" + message,
+				},
+			},
+		},
+	}
+	container.Env = append(container.Env, rconPasswordEnvVar(server))
+}
+
+// terminationGracePeriodSeconds is how long Kubernetes should wait for the server to stop before killing it. This
+// needs to cover the whole of the preStop hook set up by addGracefulShutdown.
+func terminationGracePeriodSeconds(server *minecraftv1alpha1.MinecraftServer) *int64 {
+	countdown, stopTimeout, _ := shutdownSettings(server)
+	period := int64(countdown) + int64(stopTimeout) + shutdownGracePeriodMarginSeconds
+	return &period
+}
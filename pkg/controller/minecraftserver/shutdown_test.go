@@ -0,0 +1,39 @@
+package minecraftserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		server := generateTestServer()
+		var container corev1.Container
+		addGracefulShutdown(&server, &container)
+		require.NotNil(t, container.Lifecycle)
+		assert.Equal(t, []string{serverAgentDir + "/server-agent", "shutdown", "--countdown=30s", "--stop-timeout=60s",
+			"--message=Server is restarting"}, container.Lifecycle.PreStop.Exec.Command)
+		assert.Equal(t, int64(100), *terminationGracePeriodSeconds(&server))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		server := generateTestServer()
+		countdown := int32(0)
+		stopTimeout := int32(300)
+		server.Spec.Shutdown = &v1alpha1.ShutdownSpec{
+			CountdownSeconds:   &countdown,
+			StopTimeoutSeconds: &stopTimeout,
+			Message:            "Back soon!",
+		}
+		var container corev1.Container
+		addGracefulShutdown(&server, &container)
+		assert.Equal(t, []string{serverAgentDir + "/server-agent", "shutdown", "--countdown=0s", "--stop-timeout=300s",
+			"--message=Back soon!"}, container.Lifecycle.PreStop.Exec.Command)
+		assert.Equal(t, int64(310), *terminationGracePeriodSeconds(&server))
+	})
+}
@@ -0,0 +1,31 @@
+package minecraftserver
+
+import (
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/bibliothek"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/fabricmeta"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/mojang"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/neoforge"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/purpur"
+)
+
+// Sources are the clients for the APIs that say which builds of each type of server there are, and where to download
+// them from.
+type Sources struct {
+	// Mojang finds vanilla server downloads. It caches what it finds, so it should be shared between reconciles.
+	Mojang     *mojang.Client
+	Paper      *bibliothek.Client
+	Purpur     *purpur.Client
+	FabricMeta *fabricmeta.Client
+	NeoForge   *neoforge.Client
+}
+
+// DefaultSources uses the public API for each type of server.
+func DefaultSources() *Sources {
+	return &Sources{
+		Mojang:     mojang.NewClient(mojang.DefaultBaseURL),
+		Paper:      bibliothek.NewClient(bibliothek.DefaultBaseURL),
+		Purpur:     purpur.NewClient(purpur.DefaultBaseURL),
+		FabricMeta: fabricmeta.NewClient(fabricmeta.DefaultBaseURL),
+		NeoForge:   neoforge.NewClient(neoforge.DefaultBaseURL),
+	}
+}
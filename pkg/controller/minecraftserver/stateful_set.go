@@ -0,0 +1,246 @@
+package minecraftserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+)
+
+// volumeClaimTemplatesHashAnnotation is set on a StatefulSet to record a hash of the volume claim templates we
+// generated for it. They can't be changed once the StatefulSet is created, so if they differ we replace it.
+const volumeClaimTemplatesHashAnnotation = "minecraft.jameslaverack.com/volume-claim-templates-hash"
+
+func StatefulSet(ctx context.Context, k8s client.Client, sources *Sources, server *minecraftv1alpha1.MinecraftServer) (bool, error) {
+	log := logutil.FromContextOrNew(ctx)
+
+	var actualSTS appsv1.StatefulSet
+	err := k8s.Get(ctx, client.ObjectKey{Name: server.Name, Namespace: server.Namespace}, &actualSTS)
+	if client.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, "error performing GET on StatefulSet")
+	}
+	exists := err == nil
+	if exists && actualSTS.DeletionTimestamp != nil {
+		log.Info("Waiting for old StatefulSet to be deleted")
+		return true, nil
+	}
+
+	// Unless the server type or Minecraft version has changed we stick with the build we're already running, just as
+	// for a ReplicaSet.
+	pinnedBuild := ""
+	if exists {
+		pinnedBuild = pinnedBuildFor(server, actualSTS.Annotations)
+	}
+
+	expectedSTS, err := stsForServer(ctx, sources, server, pinnedBuild)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists {
+		log.Info("StatefulSet does not exist, creating")
+		return true, k8s.Create(ctx, &expectedSTS)
+	}
+
+	if !hasCorrectOwnerReference(server, &actualSTS) {
+		// Set the right owner reference. Adding it to any existing ones.
+		actualSTS.OwnerReferences = append(actualSTS.OwnerReferences, serverOwnerReference(server))
+		log.Info("StatefulSet owner references incorrect, updating")
+		return true, k8s.Update(ctx, &actualSTS)
+	}
+
+	// Volume claim templates can't be changed, so the StatefulSet has to be replaced instead. Foreground deletion makes
+	// sure the Pod is gone before we create the new one. Existing claims aren't owned by the StatefulSet, so they're
+	// kept and picked up again by the new one.
+	if actualSTS.Annotations[volumeClaimTemplatesHashAnnotation] != expectedSTS.Annotations[volumeClaimTemplatesHashAnnotation] {
+		log.Info("StatefulSet storage out of date, replacing it")
+		return true, client.IgnoreNotFound(
+			k8s.Delete(ctx, &actualSTS, client.PropagationPolicy(metav1.DeletePropagationForeground)))
+	}
+
+	// A StatefulSet's rolling update won't replace a Pod that isn't ready, so a server that's crashing on bad config
+	// would never get the fixed template. Instead, the StatefulSet only replaces its Pod when it's deleted, and we do
+	// that ourselves once the template is updated. The replacement has the same name, so it isn't created until the
+	// old Pod is completely gone.
+	if actualSTS.Annotations[podTemplateHashAnnotation] != expectedSTS.Annotations[podTemplateHashAnnotation] {
+		log.With(
+			zap.String("actual-hash", actualSTS.Annotations[podTemplateHashAnnotation]),
+			zap.String("expected-hash", expectedSTS.Annotations[podTemplateHashAnnotation])).
+			Info("StatefulSet pod template out of date, updating")
+		if actualSTS.Annotations == nil {
+			actualSTS.Annotations = make(map[string]string)
+		}
+		for k, v := range expectedSTS.Annotations {
+			actualSTS.Annotations[k] = v
+		}
+		actualSTS.Spec.Template = expectedSTS.Spec.Template
+		actualSTS.Spec.Replicas = expectedSTS.Spec.Replicas
+		return true, k8s.Update(ctx, &actualSTS)
+	}
+
+	// StatefulSets created by older versions of the operator use rolling updates
+	if actualSTS.Spec.UpdateStrategy.Type != expectedSTS.Spec.UpdateStrategy.Type {
+		log.Info("StatefulSet update strategy incorrect, updating")
+		actualSTS.Spec.UpdateStrategy = expectedSTS.Spec.UpdateStrategy
+		return true, k8s.Update(ctx, &actualSTS)
+	}
+
+	done, err := deleteOutdatedStatefulSetPods(ctx, k8s, &actualSTS)
+	if err != nil || done {
+		return done, err
+	}
+
+	server.Status.ServerBuild = actualSTS.Annotations[serverBuildAnnotation]
+
+	log.Debug("StatefulSet OK")
+	return false, nil
+}
+
+// deleteOutdatedStatefulSetPods deletes any of the StatefulSet's Pods that were created from an older revision of its
+// Pod template, so that the StatefulSet replaces them with the current one. It returns true if it's waiting on the
+// StatefulSet controller or has deleted a Pod.
+func deleteOutdatedStatefulSetPods(ctx context.Context, k8s client.Client, sts *appsv1.StatefulSet) (bool, error) {
+	log := logutil.FromContextOrNew(ctx)
+
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == "" {
+		// We'll be triggered again when the StatefulSet's status is updated
+		log.Info("Waiting for StatefulSet controller to observe the current Pod template")
+		return true, nil
+	}
+
+	var pods corev1.PodList
+	err := k8s.List(ctx, &pods, client.InNamespace(sts.Namespace), client.MatchingLabels(sts.Spec.Selector.MatchLabels))
+	if err != nil {
+		return false, errors.Wrap(err, "error listing Pods")
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !metav1.IsControlledBy(pod, sts) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Labels[appsv1.StatefulSetRevisionLabel] == sts.Status.UpdateRevision {
+			continue
+		}
+		log.With(
+			zap.String("pod", pod.Name),
+			zap.String("pod-revision", pod.Labels[appsv1.StatefulSetRevisionLabel]),
+			zap.String("update-revision", sts.Status.UpdateRevision)).
+			Info("Server Pod out of date, deleting it")
+		return true, client.IgnoreNotFound(k8s.Delete(ctx, pod))
+	}
+	return false, nil
+}
+
+// stsForServer generates the StatefulSet for the server. The Pod template is the same as for a ReplicaSet, except that
+// volumes which are persisted come from volume claim templates.
+func stsForServer(ctx context.Context, sources *Sources, server *minecraftv1alpha1.MinecraftServer, pinnedBuild string) (appsv1.StatefulSet, error) {
+	rs, err := rsForServer(ctx, sources, server, pinnedBuild)
+	if err != nil {
+		return appsv1.StatefulSet{}, err
+	}
+
+	claims := volumeClaimTemplates(server)
+	template := rs.Spec.Template
+	var volumes []corev1.Volume
+	for _, v := range template.Spec.Volumes {
+		if !hasClaimTemplate(claims, v.Name) {
+			volumes = append(volumes, v)
+		}
+	}
+	template.Spec.Volumes = volumes
+
+	claimsHash, err := volumeClaimTemplatesHash(claims)
+	if err != nil {
+		return appsv1.StatefulSet{}, err
+	}
+	rs.Annotations[volumeClaimTemplatesHashAnnotation] = claimsHash
+
+	return appsv1.StatefulSet{
+		ObjectMeta: rs.ObjectMeta,
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: rs.Spec.Replicas,
+			Selector: rs.Spec.Selector,
+			Template: template,
+			// The Pod is replaced one at a time, and only once the old one has completely terminated. With one replica
+			// that means there's never more than one server with the world open.
+			PodManagementPolicy: appsv1.OrderedReadyPodManagement,
+			// We delete out of date Pods ourselves, see StatefulSet.
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.OnDeleteStatefulSetStrategyType,
+			},
+			VolumeClaimTemplates: claims,
+		},
+	}, nil
+}
+
+// volumeClaimTemplates generates a claim template for each kind of storage asked for in spec.workload.storage.
+func volumeClaimTemplates(server *minecraftv1alpha1.MinecraftServer) []corev1.PersistentVolumeClaim {
+	if server.Spec.Workload == nil || server.Spec.Workload.Storage == nil {
+		return nil
+	}
+	storage := server.Spec.Workload.Storage
+	claim := func(name string, size resource.Quantity) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: podLabels(server),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: storage.StorageClassName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: size,
+					},
+				},
+			},
+		}
+	}
+
+	var claims []corev1.PersistentVolumeClaim
+	if storage.World != nil && server.Spec.World == nil {
+		claims = append(claims, claim(overworldMountName, *storage.World))
+		// Only Paper keeps the other dimensions outside the world directory
+		if server.Spec.Type.IsPaperBased() {
+			claims = append(claims, claim(netherMountName, *storage.World), claim(theEndMountName, *storage.World))
+		}
+	}
+	if storage.PluginData != nil && server.Spec.Type.IsPaperBased() && persistence(server).PluginData == nil {
+		claims = append(claims, claim(pluginsMountName, *storage.PluginData))
+	}
+	if storage.ServerState != nil && persistence(server).ServerState == nil {
+		claims = append(claims, claim(workingDirVolumeName(server), *storage.ServerState))
+	}
+	if storage.Dynmap != nil && server.Spec.Dynmap != nil && server.Spec.Dynmap.Enabled && server.Spec.Dynmap.MapStorage == nil {
+		claims = append(claims, claim(dynmapDataMountName, *storage.Dynmap))
+	}
+	return claims
+}
+
+func hasClaimTemplate(claims []corev1.PersistentVolumeClaim, name string) bool {
+	for _, c := range claims {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func volumeClaimTemplatesHash(claims []corev1.PersistentVolumeClaim) (string, error) {
+	d, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(d)), nil
+}
@@ -0,0 +1,159 @@
+package minecraftserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+)
+
+func claimNames(claims []corev1.PersistentVolumeClaim) []string {
+	var names []string
+	for _, c := range claims {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestVolumeClaimTemplates(t *testing.T) {
+	size := resource.MustParse("10Gi")
+	storageClass := "fast"
+
+	t.Run("no storage", func(t *testing.T) {
+		server := generateTestServer()
+		server.Spec.Workload = &v1alpha1.WorkloadSpec{Type: v1alpha1.WorkloadTypeStatefulSet}
+		assert.Empty(t, volumeClaimTemplates(&server))
+	})
+
+	t.Run("paper", func(t *testing.T) {
+		server := generateTestServer()
+		server.Spec.Dynmap = &v1alpha1.DynmapSpec{Enabled: true}
+		server.Spec.Workload = &v1alpha1.WorkloadSpec{
+			Type: v1alpha1.WorkloadTypeStatefulSet,
+			Storage: &v1alpha1.StorageSpec{
+				StorageClassName: &storageClass,
+				World:            &size,
+				PluginData:       &size,
+				Dynmap:           &size,
+			},
+		}
+		claims := volumeClaimTemplates(&server)
+		assert.Equal(t,
+			[]string{overworldMountName, netherMountName, theEndMountName, pluginsMountName, dynmapDataMountName},
+			claimNames(claims))
+		for _, c := range claims {
+			assert.Equal(t, "fast", *c.Spec.StorageClassName)
+			assert.Equal(t, size, c.Spec.Resources.Requests[corev1.ResourceStorage])
+		}
+	})
+
+	t.Run("existing world claims", func(t *testing.T) {
+		server := generateTestServer()
+		server.Spec.World = &v1alpha1.WorldSpec{Overworld: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "world"}}
+		server.Spec.Workload = &v1alpha1.WorkloadSpec{
+			Type:    v1alpha1.WorkloadTypeStatefulSet,
+			Storage: &v1alpha1.StorageSpec{World: &size},
+		}
+		assert.Empty(t, volumeClaimTemplates(&server))
+	})
+}
+
+func TestStsForServer(t *testing.T) {
+	size := resource.MustParse("20Gi")
+	server := generateTestServer()
+	server.Spec.Type = v1alpha1.ServerTypeForge
+	server.Spec.Forge = &v1alpha1.ForgeSpec{ForgeVersion: "43.1.1"}
+	server.Spec.Workload = &v1alpha1.WorkloadSpec{
+		Type:    v1alpha1.WorkloadTypeStatefulSet,
+		Storage: &v1alpha1.StorageSpec{World: &size, ServerState: &size},
+	}
+
+	sts, err := stsForServer(context.Background(), &Sources{}, &server, "")
+	require.NoError(t, err)
+	assertOwnerReference(t, &server, &sts)
+	assert.Equal(t, int32(1), *sts.Spec.Replicas)
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, sts.Spec.PodManagementPolicy)
+	assert.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, sts.Spec.UpdateStrategy.Type)
+	assert.Equal(t, []string{overworldMountName, forgeWorkingDirVolumeName}, claimNames(sts.Spec.VolumeClaimTemplates))
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		assert.NotContains(t, []string{overworldMountName, forgeWorkingDirVolumeName}, v.Name,
+			"volume should come from the claim template")
+	}
+	assert.NotEmpty(t, sts.Annotations[podTemplateHashAnnotation])
+	original := sts.Annotations[volumeClaimTemplatesHashAnnotation]
+	assert.NotEmpty(t, original)
+
+	bigger := resource.MustParse("40Gi")
+	server.Spec.Workload.Storage.World = &bigger
+	sts, err = stsForServer(context.Background(), &Sources{}, &server, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, original, sts.Annotations[volumeClaimTemplatesHashAnnotation])
+}
+
+func TestDeleteOutdatedStatefulSetPods(t *testing.T) {
+	sts := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "sts-uid", Generation: 2},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "minecraft", "minecraft": "test"}},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "test-new"},
+	}
+	pod := func(revision string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                           "minecraft",
+				"minecraft":                     "test",
+				appsv1.StatefulSetRevisionLabel: revision,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Name:       "test",
+				UID:        "sts-uid",
+				Controller: pointer.Bool(true),
+			}},
+		}}
+	}
+	ctx := context.Background()
+
+	t.Run("up to date", func(t *testing.T) {
+		k8s := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod("test-new")).Build()
+		done, err := deleteOutdatedStatefulSetPods(ctx, k8s, &sts)
+		require.NoError(t, err)
+		assert.False(t, done)
+		require.NoError(t, k8s.Get(ctx, client.ObjectKey{Name: "test-0", Namespace: "default"}, &corev1.Pod{}))
+	})
+
+	t.Run("out of date", func(t *testing.T) {
+		k8s := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod("test-old")).Build()
+		done, err := deleteOutdatedStatefulSetPods(ctx, k8s, &sts)
+		require.NoError(t, err)
+		assert.True(t, done)
+		err = k8s.Get(ctx, client.ObjectKey{Name: "test-0", Namespace: "default"}, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("status not yet updated", func(t *testing.T) {
+		k8s := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod("test-old")).Build()
+		stale := sts.DeepCopy()
+		stale.Status.ObservedGeneration = 1
+		done, err := deleteOutdatedStatefulSetPods(ctx, k8s, stale)
+		require.NoError(t, err)
+		assert.True(t, done)
+		require.NoError(t, k8s.Get(ctx, client.ObjectKey{Name: "test-0", Namespace: "default"}, &corev1.Pod{}))
+	})
+}
@@ -0,0 +1,180 @@
+package minecraftserver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
+)
+
+// Reasons used on the conditions we set
+const (
+	reasonReconciled    = "Reconciled"
+	reasonUpdating      = "Updating"
+	reasonError         = "Error"
+	reasonAccepted      = "Accepted"
+	reasonNotAccepted   = "NotAccepted"
+	reasonPodNotFound   = "PodNotFound"
+	reasonPodNotReady   = "PodNotReady"
+	reasonPodReady      = "PodReady"
+	reasonNotReconciled = "NotReconciled"
+	reasonPingFailed    = "PingFailed"
+	reasonPingSucceeded = "PingSucceeded"
+)
+
+// pingTimeout is how long we'll wait for a server to respond to a ping
+const pingTimeout = time.Second * 5
+
+func setCondition(server *minecraftv1alpha1.MinecraftServer, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&server.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: server.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// markFailed records that reconciliation of the resources covered by the given condition failed. The error message is
+// surfaced on both the condition and the status, as that's usually the most useful thing to see in kubectl.
+func markFailed(server *minecraftv1alpha1.MinecraftServer, conditionType string, err error) {
+	setCondition(server, conditionType, metav1.ConditionFalse, reasonError, err.Error())
+	server.Status.State = minecraftv1alpha1.StateError
+	server.Status.Message = err.Error()
+}
+
+// markUpdating records that we've just made changes to the resources covered by the given condition.
+func markUpdating(server *minecraftv1alpha1.MinecraftServer, conditionType string, message string) {
+	setCondition(server, conditionType, metav1.ConditionFalse, reasonUpdating, message)
+	server.Status.State = minecraftv1alpha1.StatePending
+	server.Status.Message = message
+}
+
+func setEULACondition(server *minecraftv1alpha1.MinecraftServer) {
+	if server.Spec.EULA == minecraftv1alpha1.EULAAcceptanceAccepted {
+		setCondition(server, minecraftv1alpha1.ConditionEULAAccepted, metav1.ConditionTrue, reasonAccepted, "")
+		return
+	}
+	setCondition(server, minecraftv1alpha1.ConditionEULAAccepted, metav1.ConditionFalse, reasonNotAccepted,
+		"The Minecraft EULA must be accepted by setting spec.eula to Accepted, the server will not start without it")
+}
+
+// observeWorkload looks at the Pod (if any) running the server, and reports on it in the status. The Pod is returned
+// if it's ready.
+func observeWorkload(ctx context.Context, k8s client.Client, server *minecraftv1alpha1.MinecraftServer) (*corev1.Pod, error) {
+	var pods corev1.PodList
+	err := k8s.List(ctx, &pods, client.InNamespace(server.Namespace), client.MatchingLabels(podLabels(server)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore anything already on its way out, and if there's more than one left prefer the newest.
+	var candidates []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			candidates = append(candidates, pod)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[j].CreationTimestamp.Before(&candidates[i].CreationTimestamp)
+	})
+
+	if len(candidates) == 0 {
+		server.Status.PodName = ""
+		server.Status.NodeName = ""
+		setCondition(server, minecraftv1alpha1.ConditionWorkloadReady, metav1.ConditionFalse, reasonPodNotFound,
+			"No Pod is running the server")
+		return nil, nil
+	}
+
+	pod := candidates[0]
+	server.Status.PodName = pod.Name
+	server.Status.NodeName = pod.Spec.NodeName
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			setCondition(server, minecraftv1alpha1.ConditionWorkloadReady, metav1.ConditionTrue, reasonPodReady, "")
+			return &pod, nil
+		}
+	}
+	setCondition(server, minecraftv1alpha1.ConditionWorkloadReady, metav1.ConditionFalse, reasonPodNotReady,
+		"Pod "+pod.Name+" is "+string(pod.Status.Phase)+" and not yet ready")
+	return nil, nil
+}
+
+// pingServer checks that the server in the given Pod is responding to Server List Pings, and reports on it in the
+// status. A nil Pod means the Pod isn't ready, so we don't even try.
+func pingServer(ctx context.Context, ping PingFunc, server *minecraftv1alpha1.MinecraftServer, pod *corev1.Pod) *slp.Status {
+	if pod == nil || pod.Status.PodIP == "" {
+		setCondition(server, minecraftv1alpha1.ConditionResponding, metav1.ConditionFalse, reasonPodNotReady,
+			"Server Pod is not ready")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	status, err := ping(ctx, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(minecraftPort)))
+	if err != nil {
+		logutil.FromContextOrNew(ctx).With(zap.Error(err)).Info("Failed to ping server")
+		setCondition(server, minecraftv1alpha1.ConditionResponding, metav1.ConditionFalse, reasonPingFailed, err.Error())
+		return nil
+	}
+	setCondition(server, minecraftv1alpha1.ConditionResponding, metav1.ConditionTrue, reasonPingSucceeded, "")
+	return status
+}
+
+// summariseStatus sets the Available condition and the overall state from the other conditions. It assumes that no
+// error was encountered during this reconciliation, as errors are recorded directly by markFailed.
+func summariseStatus(server *minecraftv1alpha1.MinecraftServer) {
+	required := []string{
+		minecraftv1alpha1.ConditionEULAAccepted,
+		minecraftv1alpha1.ConditionConfigReady,
+		minecraftv1alpha1.ConditionServiceReady,
+		minecraftv1alpha1.ConditionWorkloadReady,
+	}
+	// We only check servers are responding if the operator is configured to do so
+	if meta.FindStatusCondition(server.Status.Conditions, minecraftv1alpha1.ConditionResponding) != nil {
+		required = append(required, minecraftv1alpha1.ConditionResponding)
+	}
+	for _, t := range required {
+		c := meta.FindStatusCondition(server.Status.Conditions, t)
+		if c == nil || c.Status != metav1.ConditionTrue {
+			reason := reasonNotReconciled
+			message := t + " is not yet known"
+			if c != nil {
+				reason = c.Reason
+				message = t + " is " + string(c.Status)
+				if c.Message != "" {
+					message += ": " + c.Message
+				}
+			}
+			setCondition(server, minecraftv1alpha1.ConditionAvailable, metav1.ConditionFalse, reason, message)
+			server.Status.State = minecraftv1alpha1.StatePending
+			server.Status.Message = message
+			return
+		}
+	}
+	setCondition(server, minecraftv1alpha1.ConditionAvailable, metav1.ConditionTrue, reasonReconciled, "")
+	server.Status.State = minecraftv1alpha1.StateRunning
+	server.Status.Message = ""
+}
+
+// updateStatus writes the status back to the API server, but only if it's actually changed from what we started with.
+func updateStatus(ctx context.Context, k8s client.Client, server *minecraftv1alpha1.MinecraftServer, original *minecraftv1alpha1.MinecraftServerStatus) error {
+	if equality.Semantic.DeepEqual(original, &server.Status) {
+		return nil
+	}
+	logutil.FromContextOrNew(ctx).Debug("Status changed, updating")
+	return k8s.Status().Update(ctx, server)
+}
@@ -1,11 +1,99 @@
 package minecraftserver
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
 )
 
+// minecraftPort is the port the Minecraft server listens on for players
+const minecraftPort = 25565
+
+// rconPort is the port the Minecraft server listens on for RCON
+const rconPort = 25575
+
+// bedrockPort and bedrockPortV6 are the UDP ports a Bedrock server listens on for players over IPv4 and IPv6.
+const (
+	bedrockPort   = 19132
+	bedrockPortV6 = 19133
+)
+
+// serverBuildAnnotation is set on the workload to record which build of the server software it runs, e.g., the Paper
+// build number. We read this back to report it in the status.
+const serverBuildAnnotation = "minecraft.jameslaverack.com/server-build"
+
+// minecraftVersionAnnotation is set on the workload to record the Minecraft version that the server build was resolved
+// for.
+const minecraftVersionAnnotation = "minecraft.jameslaverack.com/minecraft-version"
+
+// serverTypeAnnotation is set on the workload to record the type of server that the server build was resolved for. A
+// Paper build number means nothing to Purpur, so we mustn't carry it over if the type changes.
+const serverTypeAnnotation = "minecraft.jameslaverack.com/server-type"
+
+// podTemplateHashAnnotation is set on the workload to record a hash of the pod template we generated for it. We
+// compare this to detect when the spec has changed and the server needs to be restarted.
+const podTemplateHashAnnotation = "minecraft.jameslaverack.com/pod-template-hash"
+
+// configHashAnnotation is set on the pod template to record a hash of the server's configuration files. The config is
+// copied into the Pod when it starts, so this makes sure changes to the config cause a restart.
+const configHashAnnotation = "minecraft.jameslaverack.com/config-hash"
+
+// Names of the server's volumes that can be persisted. A StatefulSet provides these from volume claim templates.
+const (
+	overworldMountName  = "world-overworld"
+	netherMountName     = "world-nether"
+	theEndMountName     = "world-the-end"
+	pluginsMountName    = "plugins"
+	dynmapDataMountName = "dynmap-data"
+	// The server's working directory, which holds the rest of its state
+	paperWorkingDirVolumeName   = "paper-workingdir"
+	forgeWorkingDirVolumeName   = "forge-workingdir"
+	vanillaWorkingDirVolumeName = "vanilla-workingdir"
+	fabricWorkingDirVolumeName  = "fabric-workingdir"
+	bedrockWorkingDirVolumeName = "bedrock-workingdir"
+)
+
+// workingDirVolumeName is the name of the volume used for the server's working directory.
+func workingDirVolumeName(server *minecraftv1alpha1.MinecraftServer) string {
+	switch {
+	case server.Spec.Type.IsForgeBased():
+		return forgeWorkingDirVolumeName
+	case server.Spec.Type == minecraftv1alpha1.ServerTypeVanilla:
+		return vanillaWorkingDirVolumeName
+	case server.Spec.Type == minecraftv1alpha1.ServerTypeFabric:
+		return fabricWorkingDirVolumeName
+	case server.Spec.Type == minecraftv1alpha1.ServerTypeBedrock:
+		return bedrockWorkingDirVolumeName
+	default:
+		return paperWorkingDirVolumeName
+	}
+}
+
+// persistence is the server's spec.persistence, or an empty one if it isn't set.
+func persistence(server *minecraftv1alpha1.MinecraftServer) minecraftv1alpha1.PersistenceSpec {
+	if server.Spec.Persistence == nil {
+		return minecraftv1alpha1.PersistenceSpec{}
+	}
+	return *server.Spec.Persistence
+}
+
+// persistentVolume is a volume backed by the given claim, or an EmptyDir if there isn't one.
+func persistentVolume(name string, claim *corev1.PersistentVolumeClaimVolumeSource) corev1.Volume {
+	if claim != nil {
+		return corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: claim},
+		}
+	}
+	return corev1.Volume{
+		Name:         name,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+}
+
 func serverOwnerReference(server *minecraftv1alpha1.MinecraftServer) metav1.OwnerReference {
 	return *metav1.NewControllerRef(server, minecraftv1alpha1.GroupVersion.WithKind("MinecraftServer"))
 }
@@ -23,9 +111,30 @@ func hasCorrectOwnerReference(server *minecraftv1alpha1.MinecraftServer, actual
 	return false
 }
 
+// serverResources is the resources for the Minecraft container. These are normally set by the defaulting webhook, but
+// we fall back to the same defaults here in case they weren't.
+func serverResources(server *minecraftv1alpha1.MinecraftServer) corev1.ResourceRequirements {
+	if server.Spec.Resources != nil {
+		return *server.Spec.Resources
+	}
+	return minecraftv1alpha1.DefaultResources(server.Spec.Type)
+}
+
 func podLabels(server *minecraftv1alpha1.MinecraftServer) map[string]string {
 	return map[string]string{
 		"app":       "minecraft",
 		"minecraft": server.Name,
 	}
 }
+
+// serverForPod maps a Pod back to the MinecraftServer that it's running, using the labels we put on it.
+func serverForPod(pod client.Object) []reconcile.Request {
+	labels := pod.GetLabels()
+	if labels["app"] != "minecraft" || labels["minecraft"] == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{
+		Name:      labels["minecraft"],
+		Namespace: pod.GetNamespace(),
+	}}}
+}
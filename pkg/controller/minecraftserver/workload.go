@@ -0,0 +1,63 @@
+package minecraftserver
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minecraftv1alpha1 "github.com/jameslaverack/kubernetes-minecraft-operator/api/v1alpha1"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/logutil"
+)
+
+func workloadType(server *minecraftv1alpha1.MinecraftServer) minecraftv1alpha1.WorkloadType {
+	if server.Spec.Workload == nil || server.Spec.Workload.Type == "" {
+		return minecraftv1alpha1.WorkloadTypeReplicaSet
+	}
+	return server.Spec.Workload.Type
+}
+
+// Workload reconciles the workload that runs the server, either a ReplicaSet or a StatefulSet. If the workload type
+// has changed, the old workload and its Pod are removed completely before the new one is created, so that two servers
+// never have the same world open.
+func Workload(ctx context.Context, k8s client.Client, sources *Sources, server *minecraftv1alpha1.MinecraftServer) (bool, error) {
+	switch workloadType(server) {
+	case minecraftv1alpha1.WorkloadTypeStatefulSet:
+		done, err := removeWorkload(ctx, k8s, server, &appsv1.ReplicaSet{})
+		if err != nil || done {
+			return done, err
+		}
+		return StatefulSet(ctx, k8s, sources, server)
+	default:
+		done, err := removeWorkload(ctx, k8s, server, &appsv1.StatefulSet{})
+		if err != nil || done {
+			return done, err
+		}
+		return ReplicaSet(ctx, k8s, sources, server)
+	}
+}
+
+// removeWorkload deletes the server's workload of the given type, if it exists. It uses foreground deletion, so the
+// workload isn't gone until its Pod is, and returns true until then.
+func removeWorkload(ctx context.Context, k8s client.Client, server *minecraftv1alpha1.MinecraftServer, workload client.Object) (bool, error) {
+	log := logutil.FromContextOrNew(ctx)
+
+	err := k8s.Get(ctx, client.ObjectKey{Name: server.Name, Namespace: server.Namespace}, workload)
+	if client.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, "error performing GET on old workload")
+	}
+	if err != nil || !hasCorrectOwnerReference(server, workload) {
+		// Nothing to remove, or not ours to remove
+		return false, nil
+	}
+	if workload.GetDeletionTimestamp() != nil {
+		log.Info("Waiting for old workload to be deleted")
+		return true, nil
+	}
+
+	log.Info("Workload type has changed, deleting old workload")
+	return true, client.IgnoreNotFound(
+		k8s.Delete(ctx, workload, client.PropagationPolicy(metav1.DeletePropagationForeground)))
+}
@@ -0,0 +1,82 @@
+// Package fabricmeta is a client for Fabric's meta API, which lists the versions of the Fabric loader and installer and
+// builds server launcher JARs for them.
+package fabricmeta
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/apiclient"
+)
+
+// DefaultBaseURL is where the Fabric meta API is.
+const DefaultBaseURL = "https://meta.fabricmc.net"
+
+type LoaderVersion struct {
+	Loader Version `json:"loader"`
+}
+
+type Version struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+}
+
+// Client finds versions of the Fabric loader and installer, and where to download servers that use them.
+type Client struct {
+	apiclient.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{Client: apiclient.New(baseURL)}
+}
+
+// LatestLoaderVersion finds the newest stable version of the Fabric loader for a version of Minecraft.
+func (c *Client) LatestLoaderVersion(ctx context.Context, minecraftVersion string) (string, error) {
+	var loaders []LoaderVersion
+	if err := c.GetJSON(ctx, "/v2/versions/loader/"+url.PathEscape(minecraftVersion), &loaders); err != nil {
+		return "", errors.Wrapf(err, "failed to list Fabric loader versions for Minecraft %s", minecraftVersion)
+	}
+	versions := make([]Version, len(loaders))
+	for i, l := range loaders {
+		versions[i] = l.Loader
+	}
+	v, ok := latestStable(versions)
+	if !ok {
+		return "", errors.Errorf("no stable Fabric loader for Minecraft %s", minecraftVersion)
+	}
+	return v, nil
+}
+
+// LatestInstallerVersion finds the newest stable version of the Fabric installer.
+func (c *Client) LatestInstallerVersion(ctx context.Context) (string, error) {
+	var installers []Version
+	if err := c.GetJSON(ctx, "/v2/versions/installer", &installers); err != nil {
+		return "", errors.Wrap(err, "failed to list Fabric installer versions")
+	}
+	v, ok := latestStable(installers)
+	if !ok {
+		return "", errors.New("no stable Fabric installer")
+	}
+	return v, nil
+}
+
+// ServerJarURL is where to download the server launcher for the given versions of Minecraft, the loader, and the
+// installer. The launcher downloads the rest of what it needs when it first starts.
+func (c *Client) ServerJarURL(minecraftVersion, loaderVersion, installerVersion string) string {
+	return c.BaseURL + "/v2/versions/loader/" +
+		url.PathEscape(minecraftVersion) + "/" +
+		url.PathEscape(loaderVersion) + "/" +
+		url.PathEscape(installerVersion) + "/server/jar"
+}
+
+// latestStable picks the first stable version. The meta API lists versions newest first.
+func latestStable(versions []Version) (string, bool) {
+	for _, v := range versions {
+		if v.Stable {
+			return v.Version, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,69 @@
+package fabricmeta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeMeta(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/versions/loader/1.20.4", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+  {"loader": {"separator": ".", "build": 7, "maven": "net.fabricmc:fabric-loader:0.16.0-beta.1", "version": "0.16.0-beta.1", "stable": false}},
+  {"loader": {"separator": ".", "build": 6, "maven": "net.fabricmc:fabric-loader:0.15.6", "version": "0.15.6", "stable": true}},
+  {"loader": {"separator": ".", "build": 5, "maven": "net.fabricmc:fabric-loader:0.15.5", "version": "0.15.5", "stable": true}}
+]`)
+	})
+	mux.HandleFunc("/v2/versions/loader/1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/v2/versions/installer", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+  {"url": "https://maven.fabricmc.net/net/fabricmc/fabric-installer/1.0.0/fabric-installer-1.0.0.jar", "maven": "net.fabricmc:fabric-installer:1.0.0", "version": "1.0.0", "stable": true},
+  {"url": "https://maven.fabricmc.net/net/fabricmc/fabric-installer/0.11.2/fabric-installer-0.11.2.jar", "maven": "net.fabricmc:fabric-installer:0.11.2", "version": "0.11.2", "stable": true}
+]`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatestLoaderVersion(t *testing.T) {
+	c := NewClient(fakeMeta(t).URL)
+
+	t.Run("skips unstable", func(t *testing.T) {
+		v, err := c.LatestLoaderVersion(context.Background(), "1.20.4")
+		require.NoError(t, err)
+		assert.Equal(t, "0.15.6", v)
+	})
+
+	t.Run("no loaders", func(t *testing.T) {
+		_, err := c.LatestLoaderVersion(context.Background(), "1.0")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := c.LatestLoaderVersion(context.Background(), "1.99")
+		assert.Error(t, err)
+	})
+}
+
+func TestLatestInstallerVersion(t *testing.T) {
+	c := NewClient(fakeMeta(t).URL)
+	v, err := c.LatestInstallerVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", v)
+}
+
+func TestServerJarURL(t *testing.T) {
+	c := NewClient(DefaultBaseURL)
+	assert.Equal(t,
+		"https://meta.fabricmc.net/v2/versions/loader/1.20.4/0.15.6/1.0.0/server/jar",
+		c.ServerJarURL("1.20.4", "0.15.6", "1.0.0"))
+}
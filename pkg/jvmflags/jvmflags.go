@@ -0,0 +1,116 @@
+// Package jvmflags expands named profiles into the flags to pass to the JVM when running a Minecraft server.
+package jvmflags
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+type Profile string
+
+// ProfileDefault only sets the heap size, leaving everything else to the JVM.
+const ProfileDefault Profile = "default"
+
+// ProfileAikar is Aikar's well-known tuning of the G1 garbage collector for Minecraft servers. See
+// https://docs.papermc.io/paper/aikars-flags.
+const ProfileAikar Profile = "aikar"
+
+// ProfileZGCGenerational uses the Z Garbage Collector, in generational mode where the JVM supports it. It suits large
+// heaps where pause times matter more than throughput.
+const ProfileZGCGenerational Profile = "zgc-generational"
+
+// ProfileShenandoah uses the Shenandoah low-pause garbage collector.
+const ProfileShenandoah Profile = "shenandoah"
+
+// aikarLargeHeapMiB is the heap size above which Aikar recommends a different set of G1 tuning values.
+const aikarLargeHeapMiB = 12 * 1024
+
+// Flags returns the JVM flags for a profile, for the given major version of Java (e.g., 17) and heap size in MiB. The
+// minimum and maximum heap sizes are both set to heapMiB. If heapMiB is zero, no heap size is set and the JVM picks
+// one itself.
+func Flags(profile Profile, javaVersion int, heapMiB int64) ([]string, error) {
+	var flags []string
+	if heapMiB > 0 {
+		size := strconv.FormatInt(heapMiB, 10) + "M"
+		flags = append(flags, "-Xms"+size, "-Xmx"+size)
+	}
+
+	switch profile {
+	case ProfileDefault, "":
+		return flags, nil
+	case ProfileAikar:
+		return append(flags, aikar(javaVersion, heapMiB)...), nil
+	case ProfileZGCGenerational:
+		zgc, err := zgcGenerational(javaVersion)
+		if err != nil {
+			return nil, err
+		}
+		return append(flags, zgc...), nil
+	case ProfileShenandoah:
+		if javaVersion < 11 {
+			return nil, errors.Errorf("the %s profile needs Java 11 or newer, not Java %d", profile, javaVersion)
+		}
+		return append(flags,
+			"-XX:+UseShenandoahGC",
+			"-XX:+AlwaysPreTouch",
+			"-XX:+DisableExplicitGC",
+			"-XX:+PerfDisableSharedMem"), nil
+	default:
+		return nil, errors.Errorf("unknown JVM flags profile %q", profile)
+	}
+}
+
+func aikar(javaVersion int, heapMiB int64) []string {
+	// Larger heaps get a bigger young generation, and bigger regions
+	newSize, maxNewSize, regionSize, reserve, occupancy := "30", "40", "8M", "20", "15"
+	if heapMiB > aikarLargeHeapMiB {
+		newSize, maxNewSize, regionSize, reserve, occupancy = "40", "50", "16M", "15", "20"
+	}
+	flags := []string{
+		"-XX:+UseG1GC",
+		"-XX:+ParallelRefProcEnabled",
+		"-XX:MaxGCPauseMillis=200",
+		"-XX:+UnlockExperimentalVMOptions",
+		"-XX:+DisableExplicitGC",
+		"-XX:+AlwaysPreTouch",
+		"-XX:G1NewSizePercent=" + newSize,
+		"-XX:G1MaxNewSizePercent=" + maxNewSize,
+		"-XX:G1HeapRegionSize=" + regionSize,
+		"-XX:G1ReservePercent=" + reserve,
+		"-XX:G1HeapWastePercent=5",
+		"-XX:G1MixedGCCountTarget=4",
+		"-XX:InitiatingHeapOccupancyPercent=" + occupancy,
+		"-XX:G1MixedGCLiveThresholdPercent=90",
+	}
+	// This was removed from the JVM in Java 21, which prints a warning if it's given
+	if javaVersion < 21 {
+		flags = append(flags, "-XX:G1RSetUpdatingPauseTimePercent=5")
+	}
+	return append(flags,
+		"-XX:SurvivorRatio=32",
+		"-XX:+PerfDisableSharedMem",
+		"-XX:MaxTenuringThreshold=1",
+		"-Dusing.aikars.flags=https://mcflags.emc.gs",
+		"-Daikars.new.flags=true")
+}
+
+func zgcGenerational(javaVersion int) ([]string, error) {
+	var flags []string
+	switch {
+	case javaVersion < 11:
+		return nil, errors.Errorf("the %s profile needs Java 11 or newer, not Java %d", ProfileZGCGenerational, javaVersion)
+	case javaVersion < 15:
+		// ZGC was experimental until Java 15
+		flags = []string{"-XX:+UnlockExperimentalVMOptions", "-XX:+UseZGC"}
+	case javaVersion < 21:
+		// Generational ZGC arrived in Java 21, so this is the best we can do
+		flags = []string{"-XX:+UseZGC"}
+	case javaVersion < 23:
+		flags = []string{"-XX:+UseZGC", "-XX:+ZGenerational"}
+	default:
+		// Generational mode is the default from Java 23, and the only mode from Java 24
+		flags = []string{"-XX:+UseZGC"}
+	}
+	return append(flags, "-XX:+AlwaysPreTouch", "-XX:+PerfDisableSharedMem"), nil
+}
@@ -0,0 +1,154 @@
+package jvmflags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags(t *testing.T) {
+	aikar := func(newSize, maxNewSize, regionSize, reserve, occupancy string, rsetUpdating bool) []string {
+		flags := []string{
+			"-XX:+UseG1GC",
+			"-XX:+ParallelRefProcEnabled",
+			"-XX:MaxGCPauseMillis=200",
+			"-XX:+UnlockExperimentalVMOptions",
+			"-XX:+DisableExplicitGC",
+			"-XX:+AlwaysPreTouch",
+			"-XX:G1NewSizePercent=" + newSize,
+			"-XX:G1MaxNewSizePercent=" + maxNewSize,
+			"-XX:G1HeapRegionSize=" + regionSize,
+			"-XX:G1ReservePercent=" + reserve,
+			"-XX:G1HeapWastePercent=5",
+			"-XX:G1MixedGCCountTarget=4",
+			"-XX:InitiatingHeapOccupancyPercent=" + occupancy,
+			"-XX:G1MixedGCLiveThresholdPercent=90",
+		}
+		if rsetUpdating {
+			flags = append(flags, "-XX:G1RSetUpdatingPauseTimePercent=5")
+		}
+		return append(flags,
+			"-XX:SurvivorRatio=32",
+			"-XX:+PerfDisableSharedMem",
+			"-XX:MaxTenuringThreshold=1",
+			"-Dusing.aikars.flags=https://mcflags.emc.gs",
+			"-Daikars.new.flags=true")
+	}
+	withHeap := func(heap string, flags ...string) []string {
+		return append([]string{"-Xms" + heap, "-Xmx" + heap}, flags...)
+	}
+
+	tests := []struct {
+		name        string
+		profile     Profile
+		javaVersion int
+		heapMiB     int64
+		expected    []string
+	}{
+		{
+			name:        "default",
+			profile:     ProfileDefault,
+			javaVersion: 17,
+			heapMiB:     4096,
+			expected:    withHeap("4096M"),
+		},
+		{
+			name:        "unset profile",
+			profile:     "",
+			javaVersion: 17,
+			heapMiB:     4096,
+			expected:    withHeap("4096M"),
+		},
+		{
+			name:        "default without heap",
+			profile:     ProfileDefault,
+			javaVersion: 17,
+			expected:    nil,
+		},
+		{
+			name:        "aikar",
+			profile:     ProfileAikar,
+			javaVersion: 17,
+			heapMiB:     10240,
+			expected:    withHeap("10240M", aikar("30", "40", "8M", "20", "15", true)...),
+		},
+		{
+			name:        "aikar large heap",
+			profile:     ProfileAikar,
+			javaVersion: 17,
+			heapMiB:     16384,
+			expected:    withHeap("16384M", aikar("40", "50", "16M", "15", "20", true)...),
+		},
+		{
+			name:        "aikar java 21",
+			profile:     ProfileAikar,
+			javaVersion: 21,
+			heapMiB:     10240,
+			expected:    withHeap("10240M", aikar("30", "40", "8M", "20", "15", false)...),
+		},
+		{
+			name:        "zgc java 11",
+			profile:     ProfileZGCGenerational,
+			javaVersion: 11,
+			heapMiB:     8192,
+			expected: withHeap("8192M",
+				"-XX:+UnlockExperimentalVMOptions", "-XX:+UseZGC", "-XX:+AlwaysPreTouch", "-XX:+PerfDisableSharedMem"),
+		},
+		{
+			name:        "zgc java 17",
+			profile:     ProfileZGCGenerational,
+			javaVersion: 17,
+			heapMiB:     8192,
+			expected:    withHeap("8192M", "-XX:+UseZGC", "-XX:+AlwaysPreTouch", "-XX:+PerfDisableSharedMem"),
+		},
+		{
+			name:        "zgc java 21",
+			profile:     ProfileZGCGenerational,
+			javaVersion: 21,
+			heapMiB:     8192,
+			expected: withHeap("8192M",
+				"-XX:+UseZGC", "-XX:+ZGenerational", "-XX:+AlwaysPreTouch", "-XX:+PerfDisableSharedMem"),
+		},
+		{
+			name:        "zgc java 25",
+			profile:     ProfileZGCGenerational,
+			javaVersion: 25,
+			heapMiB:     8192,
+			expected:    withHeap("8192M", "-XX:+UseZGC", "-XX:+AlwaysPreTouch", "-XX:+PerfDisableSharedMem"),
+		},
+		{
+			name:        "shenandoah",
+			profile:     ProfileShenandoah,
+			javaVersion: 17,
+			heapMiB:     8192,
+			expected: withHeap("8192M",
+				"-XX:+UseShenandoahGC", "-XX:+AlwaysPreTouch", "-XX:+DisableExplicitGC", "-XX:+PerfDisableSharedMem"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := Flags(tt.profile, tt.javaVersion, tt.heapMiB)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, flags)
+		})
+	}
+}
+
+func TestFlagsErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     Profile
+		javaVersion int
+	}{
+		{name: "unknown profile", profile: "fast", javaVersion: 17},
+		{name: "zgc java 8", profile: ProfileZGCGenerational, javaVersion: 8},
+		{name: "shenandoah java 8", profile: ProfileShenandoah, javaVersion: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Flags(tt.profile, tt.javaVersion, 4096)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package mojang is a client for Mojang's launcher metadata, which says where to download each version of the vanilla
+// Minecraft server from.
+package mojang
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/apiclient"
+)
+
+// DefaultBaseURL is where Mojang serves the version manifest from.
+const DefaultBaseURL = "https://piston-meta.mojang.com"
+
+const versionManifestPath = "/mc/game/version_manifest_v2.json"
+
+type VersionManifest struct {
+	Latest   LatestVersions `json:"latest"`
+	Versions []Version      `json:"versions"`
+}
+
+type LatestVersions struct {
+	Release  string `json:"release"`
+	Snapshot string `json:"snapshot"`
+}
+
+// Version is an entry in the version manifest. The URL points to the full details of the version.
+type Version struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	ReleaseTime string `json:"releaseTime"`
+	SHA1        string `json:"sha1"`
+}
+
+// VersionDetails is the full details of a version. We only decode the parts we need.
+type VersionDetails struct {
+	ID          string              `json:"id"`
+	Downloads   map[string]Download `json:"downloads"`
+	JavaVersion JavaVersion         `json:"javaVersion"`
+}
+
+type Download struct {
+	SHA1 string `json:"sha1"`
+	Size int64  `json:"size"`
+	URL  string `json:"url"`
+}
+
+type JavaVersion struct {
+	Component    string `json:"component"`
+	MajorVersion int    `json:"majorVersion"`
+}
+
+// Client looks up versions of Minecraft. The details of a version never change once it's released, so they're cached.
+type Client struct {
+	apiclient.Client
+
+	mu             sync.Mutex
+	serverDownload map[string]Download
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		Client:         apiclient.New(baseURL),
+		serverDownload: make(map[string]Download),
+	}
+}
+
+// ServerDownload finds the download of the server JAR for a version of Minecraft, e.g., "1.20.4".
+func (c *Client) ServerDownload(ctx context.Context, version string) (Download, error) {
+	c.mu.Lock()
+	d, ok := c.serverDownload[version]
+	c.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+
+	var manifest VersionManifest
+	if err := c.GetJSON(ctx, versionManifestPath, &manifest); err != nil {
+		return Download{}, errors.Wrap(err, "failed to get version manifest")
+	}
+	var detailsURL string
+	for _, v := range manifest.Versions {
+		if v.ID == version {
+			detailsURL = v.URL
+			break
+		}
+	}
+	if detailsURL == "" {
+		return Download{}, errors.Errorf("no such Minecraft version %q", version)
+	}
+
+	// The manifest links to the details of each version with an absolute URL
+	var details VersionDetails
+	if err := c.GetJSON(ctx, detailsURL, &details); err != nil {
+		return Download{}, errors.Wrapf(err, "failed to get details of Minecraft version %s", version)
+	}
+	d, ok = details.Downloads["server"]
+	if !ok {
+		return Download{}, errors.Errorf("Minecraft version %s has no server download", version)
+	}
+
+	c.mu.Lock()
+	c.serverDownload[version] = d
+	c.mu.Unlock()
+	return d, nil
+}
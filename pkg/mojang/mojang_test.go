@@ -0,0 +1,82 @@
+package mojang
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeMojang(t *testing.T) (*httptest.Server, *int) {
+	requests := 0
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc(versionManifestPath, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{
+  "latest": {"release": "1.20.4", "snapshot": "24w03a"},
+  "versions": [
+    {"id": "24w03a", "type": "snapshot", "url": "%[1]s/v1/packages/abc/24w03a.json"},
+    {"id": "1.20.4", "type": "release", "url": "%[1]s/v1/packages/def/1.20.4.json"},
+    {"id": "a1.0.4", "type": "old_alpha", "url": "%[1]s/v1/packages/123/a1.0.4.json"}
+  ]
+}`, server.URL)
+	})
+	mux.HandleFunc("/v1/packages/def/1.20.4.json", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{
+  "id": "1.20.4",
+  "downloads": {
+    "client": {"sha1": "fd19469fed4a4b4c15b2d5133985f0e3e7816a8a", "size": 24445539, "url": "https://example.com/client.jar"},
+    "server": {"sha1": "8dd1a28015f51b1803213892b50b7b4fc76e594d", "size": 49150256, "url": "https://example.com/server.jar"}
+  },
+  "javaVersion": {"component": "java-runtime-gamma", "majorVersion": 17}
+}`)
+	})
+	mux.HandleFunc("/v1/packages/123/a1.0.4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "a1.0.4", "downloads": {"client": {"url": "https://example.com/client.jar"}}}`)
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestServerDownload(t *testing.T) {
+	server, requests := fakeMojang(t)
+	c := NewClient(server.URL)
+
+	d, err := c.ServerDownload(context.Background(), "1.20.4")
+	require.NoError(t, err)
+	assert.Equal(t, Download{
+		SHA1: "8dd1a28015f51b1803213892b50b7b4fc76e594d",
+		Size: 49150256,
+		URL:  "https://example.com/server.jar",
+	}, d)
+	assert.Equal(t, 2, *requests)
+
+	t.Run("cached", func(t *testing.T) {
+		cached, err := c.ServerDownload(context.Background(), "1.20.4")
+		require.NoError(t, err)
+		assert.Equal(t, d, cached)
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := c.ServerDownload(context.Background(), "1.99")
+		assert.Error(t, err)
+	})
+
+	t.Run("no server download", func(t *testing.T) {
+		_, err := c.ServerDownload(context.Background(), "a1.0.4")
+		assert.Error(t, err)
+	})
+
+	t.Run("broken details", func(t *testing.T) {
+		_, err := c.ServerDownload(context.Background(), "24w03a")
+		assert.Error(t, err)
+	})
+}
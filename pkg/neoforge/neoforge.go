@@ -0,0 +1,130 @@
+// Package neoforge finds versions of NeoForge from the metadata on NeoForge's Maven repository.
+package neoforge
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/apiclient"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/version"
+)
+
+// DefaultBaseURL is NeoForge's Maven repository.
+const DefaultBaseURL = "https://maven.neoforged.net/releases"
+
+const artifactPath = "/net/neoforged/neoforge"
+
+// Metadata is a Maven artifact's maven-metadata.xml. We only decode the parts we need.
+type Metadata struct {
+	Versioning Versioning `xml:"versioning"`
+}
+
+type Versioning struct {
+	Latest   string   `xml:"latest"`
+	Release  string   `xml:"release"`
+	Versions []string `xml:"versions>version"`
+}
+
+// Client finds versions of NeoForge, and where to download their installers.
+type Client struct {
+	apiclient.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{Client: apiclient.New(baseURL)}
+}
+
+// Versions lists every version of NeoForge, for all versions of Minecraft.
+func (c *Client) Versions(ctx context.Context) ([]string, error) {
+	var metadata Metadata
+	if err := c.GetXML(ctx, artifactPath+"/maven-metadata.xml", &metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to get NeoForge Maven metadata")
+	}
+	return metadata.Versioning.Versions, nil
+}
+
+// LatestVersion finds the newest version of NeoForge for a version of Minecraft. Stable versions are preferred, but
+// the first versions for each version of Minecraft are all betas, so the newest beta is used if there isn't one.
+func (c *Client) LatestVersion(ctx context.Context, minecraftVersion string) (string, error) {
+	prefix, err := VersionPrefix(minecraftVersion)
+	if err != nil {
+		return "", err
+	}
+	versions, err := c.Versions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var stable, beta string
+	for _, v := range versions {
+		if !strings.HasPrefix(v, prefix) {
+			continue
+		}
+		if strings.Contains(v, "-") {
+			if beta == "" || compareVersions(v, beta) > 0 {
+				beta = v
+			}
+		} else if stable == "" || compareVersions(v, stable) > 0 {
+			stable = v
+		}
+	}
+	if stable != "" {
+		return stable, nil
+	}
+	if beta != "" {
+		return beta, nil
+	}
+	return "", errors.Errorf("no NeoForge versions for Minecraft %s", minecraftVersion)
+}
+
+// InstallerURL is where to download the installer for a version of NeoForge.
+func (c *Client) InstallerURL(neoForgeVersion string) string {
+	return c.BaseURL + artifactPath + "/" + url.PathEscape(neoForgeVersion) + "/" +
+		url.PathEscape("neoforge-"+neoForgeVersion+"-installer.jar")
+}
+
+// VersionPrefix is how NeoForge versions start for a version of Minecraft. NeoForge drops the leading "1." and always
+// includes the patch version, so Minecraft 1.20.4 has NeoForge versions like 20.4.190, and Minecraft 1.21 has ones like
+// 21.0.167. Minecraft's year-based versions from 26.1 onwards are used as they are, with the build number added, so
+// Minecraft 26.1 has NeoForge versions like 26.1.0.12. Only releases of Minecraft from 1.20.2 onwards are supported, as
+// NeoForge used Forge's artifacts before then.
+func VersionPrefix(minecraftVersion string) (string, error) {
+	v, err := version.Parse(minecraftVersion)
+	if err != nil {
+		return "", err
+	}
+	if !v.IsRelease() || (v.Major != 1 && v.Major < firstYearBasedMajor) {
+		return "", errors.Errorf("cannot work out NeoForge versions for Minecraft %s", minecraftVersion)
+	}
+	if v.Major >= firstYearBasedMajor {
+		return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch) + ".", nil
+	}
+	if c, _ := v.Compare(version.MustParse("1.20.2")); c < 0 {
+		return "", errors.Errorf("NeoForge needs Minecraft 1.20.2 or later, not %s", minecraftVersion)
+	}
+	return strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch) + ".", nil
+}
+
+// firstYearBasedMajor is the major version of the first of Minecraft's year-based versions, 26.1.
+const firstYearBasedMajor = 26
+
+// compareVersions compares NeoForge versions like 20.4.80-beta number by number. Anything after a "-" is ignored.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.SplitN(a, "-", 2)[0], ".")
+	bs := strings.Split(strings.SplitN(b, "-", 2)[0], ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, _ := strconv.Atoi(as[i])
+		bn, _ := strconv.Atoi(bs[i])
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(as) - len(bs)
+}
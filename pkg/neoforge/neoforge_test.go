@@ -0,0 +1,97 @@
+package neoforge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeMaven(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/net/neoforged/neoforge/maven-metadata.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>net.neoforged</groupId>
+  <artifactId>neoforge</artifactId>
+  <versioning>
+    <latest>21.0.10-beta</latest>
+    <release>21.0.10-beta</release>
+    <versions>
+      <version>20.4.80-beta</version>
+      <version>20.4.99</version>
+      <version>20.4.190</version>
+      <version>20.4.237</version>
+      <version>20.4.300-beta</version>
+      <version>21.0.2-beta</version>
+      <version>21.0.10-beta</version>
+      <version>26.1.0.3-beta</version>
+      <version>26.1.0.12-beta</version>
+      <version>26.1.1.1-beta</version>
+    </versions>
+  </versioning>
+</metadata>`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatestVersion(t *testing.T) {
+	c := NewClient(fakeMaven(t).URL)
+
+	tests := []struct {
+		minecraftVersion string
+		expected         string
+	}{
+		// Stable versions win over newer betas, and are compared numerically
+		{"1.20.4", "20.4.237"},
+		// If there are only betas, the newest is used
+		{"1.21", "21.0.10-beta"},
+		// Year-based versions don't pick up versions for a later hotfix release
+		{"26.1", "26.1.0.12-beta"},
+		{"26.1.1", "26.1.1.1-beta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.minecraftVersion, func(t *testing.T) {
+			v, err := c.LatestVersion(context.Background(), tt.minecraftVersion)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+
+	_, err := c.LatestVersion(context.Background(), "1.20.6")
+	assert.Error(t, err)
+}
+
+func TestVersionPrefix(t *testing.T) {
+	for minecraftVersion, expected := range map[string]string{
+		"1.20.2": "20.2.",
+		"1.20.4": "20.4.",
+		"1.21":   "21.0.",
+		"1.21.1": "21.1.",
+		"26.1":   "26.1.0.",
+		"26.1.1": "26.1.1.",
+		"27.2":   "27.2.0.",
+	} {
+		prefix, err := VersionPrefix(minecraftVersion)
+		require.NoError(t, err)
+		assert.Equal(t, expected, prefix, minecraftVersion)
+	}
+
+	for _, minecraftVersion := range []string{"1.20.1", "1.21-pre1", "2.1", "26.1-pre1", "24w14a", "not a version"} {
+		_, err := VersionPrefix(minecraftVersion)
+		assert.Error(t, err, minecraftVersion)
+	}
+}
+
+func TestInstallerURL(t *testing.T) {
+	c := NewClient(DefaultBaseURL)
+	assert.Equal(t,
+		"https://maven.neoforged.net/releases/net/neoforged/neoforge/20.4.237/neoforge-20.4.237-installer.jar",
+		c.InstallerURL("20.4.237"))
+}
@@ -0,0 +1,161 @@
+package propertiesfile
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ValueType is the type of value a key in server.properties takes.
+type ValueType int
+
+const (
+	String ValueType = iota
+	Bool
+	Int
+)
+
+// ServerPropertiesKeys is every key that Minecraft Java Edition understands in server.properties, and the type of value
+// it takes. This includes keys that only older or newer versions of Minecraft use, as the server will just ignore any
+// it doesn't understand.
+var ServerPropertiesKeys = map[string]ValueType{
+	"accepts-transfers":                 Bool,
+	"allow-flight":                      Bool,
+	"allow-nether":                      Bool,
+	"broadcast-console-to-ops":          Bool,
+	"broadcast-rcon-to-ops":             Bool,
+	"bug-report-link":                   String,
+	"difficulty":                        String,
+	"enable-command-block":              Bool,
+	"enable-jmx-monitoring":             Bool,
+	"enable-query":                      Bool,
+	"enable-rcon":                       Bool,
+	"enable-status":                     Bool,
+	"enforce-secure-profile":            Bool,
+	"enforce-whitelist":                 Bool,
+	"entity-broadcast-range-percentage": Int,
+	"force-gamemode":                    Bool,
+	"function-permission-level":         Int,
+	"gamemode":                          String,
+	"generate-structures":               Bool,
+	"generator-settings":                String,
+	"hardcore":                          Bool,
+	"hide-online-players":               Bool,
+	"initial-disabled-packs":            String,
+	"initial-enabled-packs":             String,
+	"level-name":                        String,
+	"level-seed":                        String,
+	"level-type":                        String,
+	"log-ips":                           Bool,
+	"max-build-height":                  Int,
+	"max-chained-neighbor-updates":      Int,
+	"max-players":                       Int,
+	"max-tick-time":                     Int,
+	"max-world-size":                    Int,
+	"motd":                              String,
+	"network-compression-threshold":     Int,
+	"online-mode":                       Bool,
+	"op-permission-level":               Int,
+	"pause-when-empty-seconds":          Int,
+	"player-idle-timeout":               Int,
+	"prevent-proxy-connections":         Bool,
+	"previews-chat":                     Bool,
+	"pvp":                               Bool,
+	"query.port":                        Int,
+	"rate-limit":                        Int,
+	"rcon.password":                     String,
+	"rcon.port":                         Int,
+	"region-file-compression":           String,
+	"require-resource-pack":             Bool,
+	"resource-pack":                     String,
+	"resource-pack-id":                  String,
+	"resource-pack-prompt":              String,
+	"resource-pack-sha1":                String,
+	"server-ip":                         String,
+	"server-port":                       Int,
+	"simulation-distance":               Int,
+	"snooper-enabled":                   Bool,
+	"spawn-animals":                     Bool,
+	"spawn-monsters":                    Bool,
+	"spawn-npcs":                        Bool,
+	"spawn-protection":                  Int,
+	"sync-chunk-writes":                 Bool,
+	"text-filtering-config":             String,
+	"text-filtering-version":            Int,
+	"use-native-transport":              Bool,
+	"view-distance":                     Int,
+	"white-list":                        Bool,
+}
+
+// BedrockServerPropertiesKeys is every key that the Bedrock Dedicated Server understands in server.properties, and the
+// type of value it takes. Bedrock uses different keys to Java Edition for many of the same settings.
+var BedrockServerPropertiesKeys = map[string]ValueType{
+	"allow-cheats":                               Bool,
+	"allow-inbound-script-debugging":             Bool,
+	"allow-list":                                 Bool,
+	"allow-outbound-script-debugging":            Bool,
+	"block-network-ids-are-hashes":               Bool,
+	"chat-restriction":                           String,
+	"client-side-chunk-generation-enabled":       Bool,
+	"compression-algorithm":                      String,
+	"compression-threshold":                      Int,
+	"content-log-file-enabled":                   Bool,
+	"correct-player-movement":                    Bool,
+	"default-player-permission-level":            String,
+	"difficulty":                                 String,
+	"disable-custom-skins":                       Bool,
+	"disable-persona":                            Bool,
+	"disable-player-interaction":                 Bool,
+	"emit-server-telemetry":                      Bool,
+	"enable-lan-visibility":                      Bool,
+	"force-gamemode":                             Bool,
+	"gamemode":                                   String,
+	"level-name":                                 String,
+	"level-seed":                                 String,
+	"max-players":                                Int,
+	"max-threads":                                Int,
+	"online-mode":                                Bool,
+	"player-idle-timeout":                        Int,
+	"player-movement-action-direction-threshold": String,
+	"player-movement-distance-threshold":         String,
+	"player-movement-duration-threshold-in-ms":   Int,
+	"player-movement-score-threshold":            Int,
+	"script-debugger-auto-attach":                String,
+	"server-authoritative-block-breaking":        Bool,
+	"server-authoritative-movement":              String,
+	"server-build-radius-ratio":                  String,
+	"server-name":                                String,
+	"server-port":                                Int,
+	"server-portv6":                              Int,
+	"texturepack-required":                       Bool,
+	"tick-distance":                              Int,
+	"view-distance":                              Int,
+}
+
+// ValidateServerProperty checks that the key is one Minecraft understands, and that the value is the right type for it.
+func ValidateServerProperty(key, value string) error {
+	return validateProperty(ServerPropertiesKeys, key, value)
+}
+
+// ValidateBedrockServerProperty is like ValidateServerProperty, but for the Bedrock Dedicated Server.
+func ValidateBedrockServerProperty(key, value string) error {
+	return validateProperty(BedrockServerPropertiesKeys, key, value)
+}
+
+func validateProperty(keys map[string]ValueType, key, value string) error {
+	t, ok := keys[key]
+	if !ok {
+		return errors.Errorf("%q is not a known server.properties key", key)
+	}
+	switch t {
+	case Bool:
+		if value != "true" && value != "false" {
+			return errors.Errorf("%q must be true or false", key)
+		}
+	case Int:
+		if _, err := strconv.Atoi(value); err != nil {
+			return errors.Errorf("%q must be a whole number", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package propertiesfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateServerProperty(t *testing.T) {
+	assert.NoError(t, ValidateServerProperty("motd", "Hello: World"))
+	assert.NoError(t, ValidateServerProperty("pvp", "false"))
+	assert.NoError(t, ValidateServerProperty("spawn-protection", "0"))
+	assert.Error(t, ValidateServerProperty("pvp", "no"))
+	assert.Error(t, ValidateServerProperty("spawn-protection", "lots"))
+	assert.Error(t, ValidateServerProperty("view-distnace", "10"))
+}
+
+func TestValidateBedrockServerProperty(t *testing.T) {
+	assert.NoError(t, ValidateBedrockServerProperty("server-name", "Hello: World"))
+	assert.NoError(t, ValidateBedrockServerProperty("allow-cheats", "true"))
+	assert.NoError(t, ValidateBedrockServerProperty("tick-distance", "6"))
+	assert.Error(t, ValidateBedrockServerProperty("allow-cheats", "yes"))
+	assert.Error(t, ValidateBedrockServerProperty("tick-distance", "far"))
+	// Java Edition keys aren't understood by Bedrock
+	assert.Error(t, ValidateBedrockServerProperty("motd", "Hello"))
+}
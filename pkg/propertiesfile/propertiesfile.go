@@ -1,15 +1,241 @@
+// Package propertiesfile reads and writes Java .properties files, like Minecraft's server.properties.
+//
+// See https://docs.oracle.com/javase/8/docs/api/java/util/Properties.html#load-java.io.Reader- for the details of the
+// format.
 package propertiesfile
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
-// Write formats a .properties file of key-value pairs, using an equals sign as a delimiter.
-func Write(keysAndValues map[string]string) string {
+const hexDigits = "0123456789ABCDEF"
+
+// Write formats a .properties file of key-value pairs, using an equals sign as a delimiter. Keys are written in sorted
+// order, so the same input always produces the same output. Each comment is written on its own line at the top of the
+// file.
+//
+// Keys and values are escaped as Java would, and anything outside of printable ASCII is written as a \uXXXX escape.
+// Older versions of Minecraft read server.properties as ISO-8859-1 and newer ones as UTF-8, so this way it works with
+// both.
+func Write(keysAndValues map[string]string, comments ...string) string {
+	keys := make([]string, 0, len(keysAndValues))
+	for k := range keysAndValues {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sb := strings.Builder{}
+	for _, c := range comments {
+		for _, line := range strings.Split(c, "\n") {
+			sb.WriteString("#")
+			sb.WriteString(escape(line, false, false))
+			sb.WriteString("\n")
+		}
+	}
+	for _, k := range keys {
+		sb.WriteString(escape(k, true, true))
+		sb.WriteString("=")
+		sb.WriteString(escape(keysAndValues[k], false, true))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// escape escapes a key, value, or comment. In keys all spaces must be escaped, in values only leading spaces need to be.
+// Comments only need non-ASCII characters escaping.
+func escape(s string, isKey, escapeSpecial bool) string {
+	sb := strings.Builder{}
+	for i, r := range s {
+		switch {
+		case !escapeSpecial && r >= 0x20 && r <= 0x7e:
+			sb.WriteRune(r)
+		case r == ' ':
+			if isKey || i == 0 {
+				sb.WriteString(`\ `)
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '\\', r == '=', r == ':', r == '#', r == '!':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\f':
+			sb.WriteString(`\f`)
+		case r < 0x20 || r > 0x7e:
+			// Characters outside the Basic Multilingual Plane need to be written as a UTF-16 surrogate pair
+			for _, u := range utf16Units(r) {
+				sb.WriteString(`\u`)
+				sb.WriteByte(hexDigits[u>>12&0xF])
+				sb.WriteByte(hexDigits[u>>8&0xF])
+				sb.WriteByte(hexDigits[u>>4&0xF])
+				sb.WriteByte(hexDigits[u&0xF])
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func utf16Units(r rune) []uint16 {
+	if r < 0x10000 {
+		return []uint16{uint16(r)}
+	}
+	r -= 0x10000
+	return []uint16{uint16(0xD800 + (r>>10)&0x3FF), uint16(0xDC00 + r&0x3FF)}
+}
+
+// Read parses a .properties file, returning the key-value pairs in it. Comments are discarded. If a key appears more
+// than once, the last value wins, just like in Java.
+func Read(r io.Reader) (map[string]string, error) {
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// A line ending in an odd number of backslashes continues on the next line, with leading whitespace removed.
+		for endsWithContinuation(line) {
+			line = line[:len(line)-1]
+			if !scanner.Scan() {
+				break
+			}
+			lineNumber++
+			line += strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+
+		key, value, err := splitLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		props[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func endsWithContinuation(line string) bool {
+	backslashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// splitLine splits a logical line into its unescaped key and value. The key ends at the first unescaped '=', ':', or
+// whitespace. Whitespace around the separator is ignored.
+func splitLine(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '=' || line[i] == ':' || line[i] == ' ' || line[i] == '\t' || line[i] == '\f' {
+			end = i
+			break
+		}
+	}
+	key, err := unescape(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	value, err := unescape(rest)
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var units []uint16
+	sb := strings.Builder{}
+	flush := func() {
+		if len(units) > 0 {
+			sb.WriteString(decodeUTF16(units))
+			units = nil
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			flush()
+			sb.WriteByte(c)
+			continue
+		}
+		// Java drops a lone backslash at the very end, as there's nothing for it to escape
+		if i == len(s)-1 {
+			break
+		}
+		i++
+		switch s[i] {
+		case 't':
+			flush()
+			sb.WriteByte('\t')
+		case 'n':
+			flush()
+			sb.WriteByte('\n')
+		case 'r':
+			flush()
+			sb.WriteByte('\r')
+		case 'f':
+			flush()
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			u, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			// Collect the UTF-16 units up, so we can put surrogate pairs back together
+			units = append(units, uint16(u))
+			i += 4
+		default:
+			flush()
+			sb.WriteByte(s[i])
+		}
+	}
+	flush()
+	return sb.String(), nil
+}
+
+func decodeUTF16(units []uint16) string {
 	sb := strings.Builder{}
-	for k, v := range keysAndValues {
-		sb.WriteString(fmt.Sprintf("%s=%s\n", k, v))
+	for i := 0; i < len(units); i++ {
+		u := rune(units[i])
+		if u >= 0xD800 && u < 0xDC00 && i+1 < len(units) && units[i+1] >= 0xDC00 && units[i+1] < 0xE000 {
+			sb.WriteRune(0x10000 + (u-0xD800)<<10 + rune(units[i+1]) - 0xDC00)
+			i++
+			continue
+		}
+		sb.WriteRune(u)
 	}
 	return sb.String()
 }
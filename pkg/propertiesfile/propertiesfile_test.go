@@ -0,0 +1,114 @@
+package propertiesfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("sorted", func(t *testing.T) {
+		assert.Equal(t, "a=1\nb=2\nc=3\n", Write(map[string]string{"c": "3", "a": "1", "b": "2"}))
+	})
+
+	t.Run("escaping", func(t *testing.T) {
+		tests := []struct {
+			key, value, expected string
+		}{
+			{"motd", "A Minecraft Server", "motd=A Minecraft Server\n"},
+			{"motd", "key=value: \\ #1 !", `motd=key\=value\: \\ \#1 \!` + "\n"},
+			{"motd", "  leading spaces", `motd=\  leading spaces` + "\n"},
+			{"motd", "two\nlines\ttab", `motd=two\nlines\ttab` + "\n"},
+			{"motd", "§6Gold — café", `motd=\u00A76Gold \u2014 caf\u00E9` + "\n"},
+			{"motd", "⛏️💎", `motd=\u26CF\uFE0F\uD83D\uDC8E` + "\n"},
+			{"a key", "", `a\ key=` + "\n"},
+		}
+		for _, tt := range tests {
+			assert.Equal(t, tt.expected, Write(map[string]string{tt.key: tt.value}))
+		}
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		assert.Equal(t, "#Minecraft server properties\n#Managed by caf\\u00E9\na=1\n",
+			Write(map[string]string{"a": "1"}, "Minecraft server properties\nManaged by café"))
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("minecraft", func(t *testing.T) {
+		// As written by a Minecraft server
+		file := `#Minecraft server properties
+#Sat Oct 15 12:00:00 UTC 2022
+enable-jmx-monitoring=false
+rcon.port=25575
+level-seed=
+gamemode=survival
+motd=§6A Minecraft Server\: welcome\!
+level-type=minecraft\:normal
+`
+		props, err := Read(strings.NewReader(file))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"enable-jmx-monitoring": "false",
+			"rcon.port":             "25575",
+			"level-seed":            "",
+			"gamemode":              "survival",
+			"motd":                  "§6A Minecraft Server: welcome!",
+			"level-type":            "minecraft:normal",
+		}, props)
+	})
+
+	t.Run("syntax", func(t *testing.T) {
+		file := "  ! a comment\n" +
+			"colon:value\n" +
+			"spaces   =   value with trailing space \n" +
+			"whitespace separated\n" +
+			"escaped\\ key=v\n" +
+			"continued=one, \\\n    two\n" +
+			"not\\\\\n" +
+			"emoji=\\uD83D\\uDC8E\n" +
+			"empty\n" +
+			"\n"
+		props, err := Read(strings.NewReader(file))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"colon":       "value",
+			"spaces":      "value with trailing space ",
+			"whitespace":  "separated",
+			"escaped key": "v",
+			"continued":   "one, two",
+			"not\\":       "",
+			"emoji":       "💎",
+			"empty":       "",
+		}, props)
+	})
+
+	t.Run("trailing backslash", func(t *testing.T) {
+		props, err := Read(strings.NewReader("motd=hello\\"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"motd": "hello"}, props)
+
+		value, err := unescape("hello\\")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", value)
+	})
+
+	t.Run("malformed escape", func(t *testing.T) {
+		_, err := Read(strings.NewReader("motd=\\u00"))
+		assert.Error(t, err)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		original := map[string]string{
+			"motd":        "  §6Hello: world = \\ #1 !\n⛏️💎",
+			"a key":       "value",
+			"level-seed":  "",
+			"max-players": "20",
+		}
+		props, err := Read(strings.NewReader(Write(original, "A comment")))
+		require.NoError(t, err)
+		assert.Equal(t, original, props)
+	})
+}
@@ -0,0 +1,57 @@
+package purpur
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Config works out the contents of purpur.yml. Both kinds of settings are keyed by dotted paths, e.g.,
+// "settings.use-alternate-keepalive". The free-form settings are parsed as YAML, so "false" is a boolean and "5" is a
+// number, and take precedence over the typed settings.
+func Config(typed map[string]interface{}, settings map[string]string) (map[string]interface{}, error) {
+	all := make(map[string]interface{}, len(typed)+len(settings))
+	for k, v := range typed {
+		all[k] = v
+	}
+	for k, v := range settings {
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(v), &value); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for Purpur setting %s", k)
+		}
+		all[k] = value
+	}
+
+	// Settings are applied in order so a conflict between two of them is always reported the same way
+	paths := make([]string, 0, len(all))
+	for k := range all {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+
+	config := make(map[string]interface{})
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		m := config
+		for _, k := range keys[:len(keys)-1] {
+			next, ok := m[k]
+			if !ok {
+				next = make(map[string]interface{})
+				m[k] = next
+			}
+			nextMap, ok := next.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("Purpur setting %s conflicts with another setting", path)
+			}
+			m = nextMap
+		}
+		last := keys[len(keys)-1]
+		if _, ok := m[last]; ok {
+			return nil, errors.Errorf("Purpur setting %s conflicts with another setting", path)
+		}
+		m[last] = all[path]
+	}
+	return config, nil
+}
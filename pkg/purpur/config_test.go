@@ -0,0 +1,37 @@
+package purpur
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("settings take precedence", func(t *testing.T) {
+		c, err := Config(
+			map[string]interface{}{"settings.use-alternate-keepalive": true},
+			map[string]string{"settings.use-alternate-keepalive": "false", "settings.max-players": "5"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"settings": map[string]interface{}{
+				"use-alternate-keepalive": false,
+				"max-players":             float64(5),
+			},
+		}, c)
+	})
+	t.Run("invalid YAML", func(t *testing.T) {
+		_, err := Config(nil, map[string]string{"settings.motd": "[unclosed"})
+		assert.Error(t, err)
+	})
+	t.Run("setting inside a value", func(t *testing.T) {
+		_, err := Config(nil, map[string]string{"settings": "5", "settings.motd": "hello"})
+		assert.Error(t, err)
+	})
+	t.Run("setting inside a typed value", func(t *testing.T) {
+		_, err := Config(
+			map[string]interface{}{"settings.use-alternate-keepalive": true},
+			map[string]string{"settings.use-alternate-keepalive.enabled": "true"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+// Package purpur is a client for Purpur's downloads API, which lists and serves the builds of Purpur. It also works out
+// the contents of Purpur's own config file, purpur.yml.
+package purpur
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/apiclient"
+)
+
+// DefaultBaseURL is where Purpur's downloads API is.
+const DefaultBaseURL = "https://api.purpurmc.org"
+
+// ResultSuccess is the result of a build that succeeded. Only these have a download.
+const ResultSuccess = "SUCCESS"
+
+type VersionResponse struct {
+	Project string `json:"project"`
+	Version string `json:"version"`
+	Builds  Builds `json:"builds"`
+}
+
+type Builds struct {
+	Latest string   `json:"latest"`
+	All    []string `json:"all"`
+}
+
+// Build is a single build of Purpur. Purpur identifies builds with strings of digits, e.g., "2176".
+type Build struct {
+	Project string `json:"project"`
+	Version string `json:"version"`
+	Build   string `json:"build"`
+	Result  string `json:"result"`
+	MD5     string `json:"md5"`
+}
+
+// Client finds builds of Purpur.
+type Client struct {
+	apiclient.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{Client: apiclient.New(baseURL)}
+}
+
+// LatestBuild finds the newest build of Purpur for a version of Minecraft.
+func (c *Client) LatestBuild(ctx context.Context, version string) (Build, error) {
+	var resp VersionResponse
+	if err := c.GetJSON(ctx, versionPath(version), &resp); err != nil {
+		return Build{}, errors.Wrapf(err, "failed to list Purpur builds for Minecraft %s", version)
+	}
+	if resp.Builds.Latest == "" {
+		return Build{}, errors.Errorf("no Purpur builds for Minecraft %s", version)
+	}
+	return c.Build(ctx, version, resp.Builds.Latest)
+}
+
+// Build gets a specific build of Purpur. It's an error if the build failed, as there's nothing to download.
+func (c *Client) Build(ctx context.Context, version, build string) (Build, error) {
+	var b Build
+	if err := c.GetJSON(ctx, versionPath(version)+"/"+url.PathEscape(build), &b); err != nil {
+		return Build{}, errors.Wrapf(err, "failed to get Purpur build %s for Minecraft %s", build, version)
+	}
+	if b.Result != ResultSuccess {
+		return Build{}, errors.Errorf("Purpur build %s for Minecraft %s has result %q", build, version, b.Result)
+	}
+	return b, nil
+}
+
+// DownloadURL is where to download the server JAR of a build.
+func (c *Client) DownloadURL(b Build) string {
+	return c.BaseURL + versionPath(b.Version) + "/" + url.PathEscape(b.Build) + "/download"
+}
+
+func versionPath(version string) string {
+	return "/v2/purpur/" + url.PathEscape(version)
+}
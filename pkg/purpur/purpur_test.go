@@ -0,0 +1,60 @@
+package purpur
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeAPI(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/purpur/1.20.4", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"project": "purpur", "version": "1.20.4", "builds": {"latest": "2176", "all": ["2175", "2176"]}}`)
+	})
+	mux.HandleFunc("/v2/purpur/1.20.4/2176", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"project": "purpur", "version": "1.20.4", "build": "2176", "result": "SUCCESS", "md5": "abc123"}`)
+	})
+	mux.HandleFunc("/v2/purpur/1.20.4/2175", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"project": "purpur", "version": "1.20.4", "build": "2175", "result": "FAILURE"}`)
+	})
+	mux.HandleFunc("/v2/purpur/1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"project": "purpur", "version": "1.0", "builds": {"latest": "", "all": []}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatestBuild(t *testing.T) {
+	c := NewClient(fakeAPI(t).URL)
+
+	t.Run("latest", func(t *testing.T) {
+		b, err := c.LatestBuild(context.Background(), "1.20.4")
+		require.NoError(t, err)
+		assert.Equal(t, "2176", b.Build)
+		assert.Equal(t, "abc123", b.MD5)
+		assert.Equal(t, c.BaseURL+"/v2/purpur/1.20.4/2176/download", c.DownloadURL(b))
+	})
+
+	t.Run("no builds", func(t *testing.T) {
+		_, err := c.LatestBuild(context.Background(), "1.0")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := c.LatestBuild(context.Background(), "1.1")
+		assert.Error(t, err)
+	})
+}
+
+func TestBuild(t *testing.T) {
+	c := NewClient(fakeAPI(t).URL)
+
+	_, err := c.Build(context.Background(), "1.20.4", "2175")
+	assert.Error(t, err, "failed builds have nothing to download")
+}
@@ -0,0 +1,28 @@
+package rcon
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// listResponse matches the response to the "list" command. Older versions of Minecraft say "There are 1/20 players
+// online:", newer ones say "There are 1 of a max of 20 players online:". The names follow, separated by commas.
+var listResponse = regexp.MustCompile(`(?s)^There are \d+(?:/| of a max of )\d+ players online:(.*)$`)
+
+// ParseListResponse parses the response to the "list" command, returning the names of the players online.
+func ParseListResponse(response string) ([]string, error) {
+	matches := listResponse.FindStringSubmatch(strings.TrimSpace(response))
+	if matches == nil {
+		return nil, errors.Errorf("unexpected response to list command: %q", response)
+	}
+	names := []string{}
+	for _, name := range strings.Split(matches[1], ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
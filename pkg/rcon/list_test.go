@@ -0,0 +1,46 @@
+package rcon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
+)
+
+func TestParseListResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected []string
+	}{
+		{
+			name:     "nobody online",
+			response: "There are 0 of a max of 20 players online: ",
+			expected: []string{},
+		},
+		{
+			name:     "players online",
+			response: "There are 2 of a max of 20 players online: Alice, Bob",
+			expected: []string{"Alice", "Bob"},
+		},
+		{
+			name:     "old format",
+			response: "There are 2/20 players online:\nAlice, Bob",
+			expected: []string{"Alice", "Bob"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := rcon.ParseListResponse(tt.response)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("unexpected response", func(t *testing.T) {
+		_, err := rcon.ParseListResponse("Unknown or incomplete command")
+		assert.Error(t, err)
+	})
+}
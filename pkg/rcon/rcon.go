@@ -0,0 +1,268 @@
+// Package rcon is a client for the Source RCON protocol, as implemented by Minecraft servers.
+//
+// See https://developer.valvesoftware.com/wiki/Source_RCON_Protocol for the details of the protocol. Minecraft has a
+// few quirks, the most important of which is that long responses are split over several packets with no indication of
+// where they end. We deal with this by following every command with a second request of an unknown type. The server
+// answers requests in order, so once we see the response to that we know we've got the whole response to the command.
+package rcon
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Packet types. Note that ExecCommand and AuthResponse share a value, which one is meant depends on the direction.
+const (
+	TypeResponseValue int32 = 0
+	TypeExecCommand   int32 = 2
+	TypeAuthResponse  int32 = 2
+	TypeAuth          int32 = 3
+)
+
+// typeEndOfResponse is the (deliberately unknown) packet type we send after every command to find the end of the
+// response.
+const typeEndOfResponse int32 = 100
+
+// MaxCommandLength is the longest command body Minecraft will accept. Minecraft limits whole packets to 1460 bytes, and
+// we need 14 bytes for the header and padding.
+const MaxCommandLength = 1446
+
+// maxPacketSize is the largest packet we'll accept from the server. Minecraft fragments responses into 4096 byte chunks
+// so this is generous.
+const maxPacketSize = 1 << 16
+
+// ErrAuthenticationFailed is returned when the server rejects the password.
+var ErrAuthenticationFailed = errors.New("rcon authentication failed")
+
+// Packet is a single RCON packet.
+type Packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// WritePacket encodes a packet to the given writer.
+func WritePacket(w io.Writer, p Packet) error {
+	// Length of the ID, type, body, and the two null bytes that terminate the body and the packet.
+	length := int32(4 + 4 + len(p.Body) + 2)
+	buf := bytes.NewBuffer(make([]byte, 0, length+4))
+	_ = binary.Write(buf, binary.LittleEndian, length)
+	_ = binary.Write(buf, binary.LittleEndian, p.ID)
+	_ = binary.Write(buf, binary.LittleEndian, p.Type)
+	buf.WriteString(p.Body)
+	buf.Write([]byte{0, 0})
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadPacket decodes a packet from the given reader.
+func ReadPacket(r io.Reader) (Packet, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return Packet{}, err
+	}
+	if length < 10 || length > maxPacketSize {
+		return Packet{}, errors.Errorf("invalid rcon packet length %d", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return Packet{}, err
+	}
+	return Packet{
+		ID:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		Type: int32(binary.LittleEndian.Uint32(data[4:8])),
+		// Strip the two trailing null bytes
+		Body: string(bytes.TrimRight(data[8:], "\x00")),
+	}, nil
+}
+
+// Client is a connection to an RCON server. It's safe for concurrent use, but commands are sent one at a time. If the
+// connection fails it's closed, and the next command will reconnect.
+type Client struct {
+	address  string
+	password string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	nextID int32
+}
+
+// Dial connects to an RCON server and authenticates.
+func Dial(ctx context.Context, address, password string) (*Client, error) {
+	c := &Client{
+		address:  address,
+		password: password,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Command executes a command on the server, returning the response. The context's deadline (if any) applies to the
+// whole exchange, including reconnecting if needed.
+func (c *Client) Command(ctx context.Context, command string) (string, error) {
+	if len(command) > MaxCommandLength {
+		return "", errors.Errorf("command is too long, %d bytes is more than the maximum of %d", len(command), MaxCommandLength)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	response, err := c.exchange(ctx, command)
+	if err != nil {
+		// We don't know what state the connection is in, so throw it away and start fresh next time.
+		c.closeConn()
+		return "", err
+	}
+	return response, nil
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *Client) closeConn() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+}
+
+func (c *Client) id() int32 {
+	c.nextID++
+	// Negative IDs are used to signal authentication failure, so never use them.
+	if c.nextID <= 0 {
+		c.nextID = 1
+	}
+	return c.nextID
+}
+
+// withContext arranges for I/O on the connection to respect the context's deadline and cancellation. The returned
+// function must be called when the I/O is finished.
+func (c *Client) withContext(ctx context.Context) func() {
+	deadline, _ := ctx.Deadline()
+	_ = c.conn.SetDeadline(deadline)
+
+	conn := c.conn
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			// Setting a deadline in the past unblocks any reads or writes in progress
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to rcon server")
+	}
+	c.conn = conn
+	defer c.withContext(ctx)()
+
+	id := c.id()
+	if err := WritePacket(c.conn, Packet{ID: id, Type: TypeAuth, Body: c.password}); err != nil {
+		c.closeConn()
+		return errors.Wrap(err, "failed to send rcon authentication")
+	}
+	for {
+		p, err := ReadPacket(c.conn)
+		if err != nil {
+			c.closeConn()
+			return errors.Wrap(contextError(ctx, err), "failed to read rcon authentication response")
+		}
+		// Some servers send an empty response value before the auth response, we can ignore it.
+		if p.Type != TypeAuthResponse {
+			continue
+		}
+		if p.ID == -1 {
+			c.closeConn()
+			return ErrAuthenticationFailed
+		}
+		if p.ID != id {
+			c.closeConn()
+			return errors.Errorf("unexpected rcon authentication response ID %d, expected %d", p.ID, id)
+		}
+		return nil
+	}
+}
+
+func (c *Client) exchange(ctx context.Context, command string) (string, error) {
+	defer c.withContext(ctx)()
+
+	commandID := c.id()
+	endID := c.id()
+	if err := WritePacket(c.conn, Packet{ID: commandID, Type: TypeExecCommand, Body: command}); err != nil {
+		return "", errors.Wrap(contextError(ctx, err), "failed to send rcon command")
+	}
+	if err := WritePacket(c.conn, Packet{ID: endID, Type: typeEndOfResponse}); err != nil {
+		return "", errors.Wrap(contextError(ctx, err), "failed to send rcon command")
+	}
+
+	var sb strings.Builder
+	for {
+		p, err := ReadPacket(c.conn)
+		if err != nil {
+			return "", errors.Wrap(contextError(ctx, err), "failed to read rcon response")
+		}
+		switch p.ID {
+		case commandID:
+			sb.WriteString(p.Body)
+		case endID:
+			return sb.String(), nil
+		case -1:
+			return "", ErrAuthenticationFailed
+		default:
+			return "", errors.Errorf("unexpected rcon response ID %d", p.ID)
+		}
+	}
+}
+
+// contextError prefers the context's error to the I/O error, as if the context is done that's the real reason the I/O
+// failed.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	// The connection's deadline can fire a moment before the context notices its own deadline has passed.
+	var netErr net.Error
+	if deadline, ok := ctx.Deadline(); ok && errors.As(err, &netErr) && netErr.Timeout() && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+package rcon_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon/rcontest"
+)
+
+func echo(command string) string {
+	return "You said: " + command
+}
+
+func TestCommand(t *testing.T) {
+	server := rcontest.NewServer("hunter2", echo)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := rcon.Dial(ctx, server.Addr, "hunter2")
+	require.NoError(t, err)
+	defer c.Close()
+
+	t.Run("simple", func(t *testing.T) {
+		resp, err := c.Command(ctx, "list")
+		require.NoError(t, err)
+		assert.Equal(t, "You said: list", resp)
+	})
+	t.Run("several in a row", func(t *testing.T) {
+		for _, cmd := range []string{"save-off", "save-all", "save-on"} {
+			resp, err := c.Command(ctx, cmd)
+			require.NoError(t, err)
+			assert.Equal(t, "You said: "+cmd, resp)
+		}
+	})
+	t.Run("too long", func(t *testing.T) {
+		_, err := c.Command(ctx, strings.Repeat("a", rcon.MaxCommandLength+1))
+		assert.Error(t, err)
+	})
+}
+
+func TestFragmentedResponse(t *testing.T) {
+	long := strings.Repeat("0123456789", 1000)
+	server := rcontest.NewServer("hunter2", func(string) string { return long })
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := rcon.Dial(ctx, server.Addr, "hunter2")
+	require.NoError(t, err)
+	defer c.Close()
+
+	resp, err := c.Command(ctx, "help")
+	require.NoError(t, err)
+	assert.Equal(t, long, resp)
+
+	// Make sure we're still in sync with the server afterwards
+	resp, err = c.Command(ctx, "help")
+	require.NoError(t, err)
+	assert.Equal(t, long, resp)
+}
+
+func TestEmptyResponse(t *testing.T) {
+	server := rcontest.NewServer("hunter2", func(string) string { return "" })
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := rcon.Dial(ctx, server.Addr, "hunter2")
+	require.NoError(t, err)
+	defer c.Close()
+
+	resp, err := c.Command(ctx, "save-all")
+	require.NoError(t, err)
+	assert.Empty(t, resp)
+}
+
+func TestWrongPassword(t *testing.T) {
+	server := rcontest.NewServer("hunter2", echo)
+	defer server.Close()
+
+	_, err := rcon.Dial(context.Background(), server.Addr, "password")
+	assert.ErrorIs(t, err, rcon.ErrAuthenticationFailed)
+}
+
+func TestReconnect(t *testing.T) {
+	server := rcontest.NewServer("hunter2", echo)
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := rcon.Dial(ctx, server.Addr, "hunter2")
+	require.NoError(t, err)
+	defer c.Close()
+
+	server.CloseClientConnections()
+
+	// The first command notices the connection is gone, and the next one reconnects.
+	_, err = c.Command(ctx, "list")
+	require.Error(t, err)
+	resp, err := c.Command(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, "You said: list", resp)
+}
+
+func TestDeadline(t *testing.T) {
+	// A server that accepts connections but never says anything
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = rcon.Dial(ctx, l.Addr().String(), "hunter2")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestCancel(t *testing.T) {
+	block := make(chan struct{})
+	server := rcontest.NewServer("hunter2", func(string) string {
+		<-block
+		return ""
+	})
+	defer server.Close()
+	defer close(block)
+
+	c, err := rcon.Dial(context.Background(), server.Addr, "hunter2")
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	_, err = c.Command(ctx, "list")
+	assert.ErrorIs(t, err, context.Canceled)
+}
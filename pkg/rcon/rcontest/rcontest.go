@@ -0,0 +1,139 @@
+// Package rcontest provides an in-process RCON server for use in tests, in the spirit of net/http/httptest. It behaves
+// like a Minecraft server does, including splitting long responses over several packets.
+package rcontest
+
+import (
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/rcon"
+)
+
+// maxResponseFragment is the largest body Minecraft will put in a single response packet.
+const maxResponseFragment = 4096
+
+// Handler is called for each command the server receives, and returns the response to send back.
+type Handler func(command string) string
+
+// Server is a fake RCON server listening on a local port.
+type Server struct {
+	// Addr is the address the server is listening on, in host:port form.
+	Addr string
+
+	password string
+	handler  Handler
+	listener net.Listener
+
+	mu       sync.Mutex
+	commands []string
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a new server that accepts the given password and answers commands using the handler.
+func NewServer(password string, handler Handler) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("rcontest: failed to listen: " + err.Error())
+	}
+	s := &Server{
+		Addr:     l.Addr().String(),
+		password: password,
+		handler:  handler,
+		listener: l,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Commands returns every command the server has received so far, in order.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// CloseClientConnections forcibly disconnects every connected client, but leaves the server running. This is useful to
+// test reconnection.
+func (s *Server) CloseClientConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+}
+
+// Close shuts down the server and disconnects all clients.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.CloseClientConnections()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	authenticated := false
+	for {
+		p, err := rcon.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p.Type {
+		case rcon.TypeAuth:
+			if p.Body != s.password {
+				_ = rcon.WritePacket(conn, rcon.Packet{ID: -1, Type: rcon.TypeAuthResponse})
+				continue
+			}
+			authenticated = true
+			_ = rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeAuthResponse})
+		case rcon.TypeExecCommand:
+			if !authenticated {
+				_ = rcon.WritePacket(conn, rcon.Packet{ID: -1, Type: rcon.TypeAuthResponse})
+				continue
+			}
+			s.mu.Lock()
+			s.commands = append(s.commands, p.Body)
+			s.mu.Unlock()
+			response := s.handler(p.Body)
+			// Like Minecraft, always send at least one packet even if the response is empty.
+			for first := true; first || len(response) > 0; first = false {
+				n := len(response)
+				if n > maxResponseFragment {
+					n = maxResponseFragment
+				}
+				if err := rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeResponseValue, Body: response[:n]}); err != nil {
+					return
+				}
+				response = response[n:]
+			}
+		default:
+			// This is what Minecraft does with requests it doesn't understand
+			_ = rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeResponseValue, Body: "Unknown request " + strconv.FormatUint(uint64(uint32(p.Type)), 16)})
+		}
+	}
+}
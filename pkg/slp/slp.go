@@ -0,0 +1,291 @@
+// Package slp is a client for the Minecraft Java Edition Server List Ping protocol. This is what the game uses to show
+// the version, MOTD, and player count of a server in the multiplayer menu.
+//
+// See https://wiki.vg/Server_List_Ping for the details of the protocol.
+package slp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// handshakeProtocolVersion is the protocol version we claim in the handshake. By convention -1 is used when pinging, as
+// servers will respond to a status request regardless.
+const handshakeProtocolVersion = -1
+
+// maxPacketLength is the largest packet we'll accept. The status response includes a base64 encoded favicon, which is
+// the biggest thing in it.
+const maxPacketLength = 1 << 20
+
+const (
+	packetIDHandshake = 0x00
+	packetIDStatus    = 0x00
+	packetIDPing      = 0x01
+)
+
+const nextStateStatus = 1
+
+// Status is the response to a Server List Ping.
+type Status struct {
+	Version     Version     `json:"version"`
+	Players     Players     `json:"players"`
+	Description Description `json:"description"`
+	// Favicon is a data URI of a 64x64 PNG image, e.g., "data:image/png;base64,...". It's empty if the server doesn't
+	// have one.
+	Favicon string `json:"favicon,omitempty"`
+	// Latency is the round trip time of a ping to the server. It's not part of the response, we measure it.
+	Latency time.Duration `json:"-"`
+}
+
+type Version struct {
+	Name     string `json:"name"`
+	Protocol int    `json:"protocol"`
+}
+
+type Players struct {
+	Max    int `json:"max"`
+	Online int `json:"online"`
+	// Sample is some of the online players. Servers usually limit this to a handful of players, so it's not a reliable
+	// way of finding out everyone who is online.
+	Sample []Player `json:"sample,omitempty"`
+}
+
+type Player struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// Description is the server's MOTD. On the wire this can either be a plain string or a chat component, which we
+// flatten to plain text.
+type Description struct {
+	Text string
+}
+
+func (d *Description) UnmarshalJSON(data []byte) error {
+	var c chatComponent
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	d.Text = c.flatten()
+	return nil
+}
+
+func (d Description) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Text)
+}
+
+// chatComponent is enough of Minecraft's chat component format to get the text out.
+type chatComponent struct {
+	Text  string          `json:"text"`
+	Extra []chatComponent `json:"extra,omitempty"`
+}
+
+func (c *chatComponent) UnmarshalJSON(data []byte) error {
+	// A component can be a bare string
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &c.Text)
+	}
+	type plain chatComponent
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+func (c chatComponent) flatten() string {
+	var sb strings.Builder
+	sb.WriteString(c.Text)
+	for _, e := range c.Extra {
+		sb.WriteString(e.flatten())
+	}
+	return sb.String()
+}
+
+// Ping performs a Server List Ping against the server at the given address, in host:port form. The context's deadline
+// (if any) applies to the whole exchange.
+func Ping(ctx context.Context, address string) (*Status, error) {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid port")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to server")
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	status, err := ping(conn, host, uint16(port))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// The connection's deadline can fire a moment before the context notices its own deadline has passed.
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && !deadline.IsZero() && !time.Now().Before(deadline) {
+			return nil, context.DeadlineExceeded
+		}
+		return nil, err
+	}
+	return status, nil
+}
+
+func ping(conn net.Conn, host string, port uint16) (*Status, error) {
+	r := bufio.NewReader(conn)
+
+	// Handshake, and then ask for the status
+	var handshake bytes.Buffer
+	WriteVarInt(&handshake, handshakeProtocolVersion)
+	writeString(&handshake, host)
+	_ = binary.Write(&handshake, binary.BigEndian, port)
+	WriteVarInt(&handshake, nextStateStatus)
+	if err := WritePacket(conn, packetIDHandshake, handshake.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "failed to send handshake")
+	}
+	if err := WritePacket(conn, packetIDStatus, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to send status request")
+	}
+
+	id, payload, err := ReadPacket(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read status response")
+	}
+	if id != packetIDStatus {
+		return nil, errors.Errorf("unexpected packet ID %d in response to status request", id)
+	}
+	statusJSON, err := readString(bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read status response")
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(statusJSON), &status); err != nil {
+		return nil, errors.Wrap(err, "failed to parse status response")
+	}
+
+	// Ping to measure latency. The server echos back whatever number we send.
+	start := time.Now()
+	var pingPayload bytes.Buffer
+	_ = binary.Write(&pingPayload, binary.BigEndian, start.UnixNano())
+	if err := WritePacket(conn, packetIDPing, pingPayload.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "failed to send ping")
+	}
+	id, payload, err = ReadPacket(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read pong")
+	}
+	if id != packetIDPing || !bytes.Equal(payload, pingPayload.Bytes()) {
+		return nil, errors.New("invalid pong from server")
+	}
+	status.Latency = time.Since(start)
+
+	return &status, nil
+}
+
+// WritePacket writes a packet with the given ID and payload, prefixed with its length.
+func WritePacket(w io.Writer, id int32, payload []byte) error {
+	var body bytes.Buffer
+	WriteVarInt(&body, id)
+	body.Write(payload)
+
+	var packet bytes.Buffer
+	WriteVarInt(&packet, int32(body.Len()))
+	packet.Write(body.Bytes())
+	_, err := w.Write(packet.Bytes())
+	return err
+}
+
+// ReadPacket reads a length-prefixed packet, returning its ID and payload.
+func ReadPacket(r io.ByteReader) (int32, []byte, error) {
+	length, err := ReadVarInt(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length <= 0 || length > maxPacketLength {
+		return 0, nil, errors.Errorf("invalid packet length %d", length)
+	}
+	data := make([]byte, length)
+	for i := range data {
+		data[i], err = r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	body := bytes.NewReader(data)
+	id, err := ReadVarInt(body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, data[len(data)-body.Len():], nil
+}
+
+// WriteVarInt writes a Minecraft protocol VarInt, which is a little-endian base 128 encoding of the two's complement
+// value.
+func WriteVarInt(w io.ByteWriter, value int32) {
+	u := uint32(value)
+	for {
+		if u&^0x7F == 0 {
+			_ = w.WriteByte(byte(u))
+			return
+		}
+		_ = w.WriteByte(byte(u&0x7F | 0x80))
+		u >>= 7
+	}
+}
+
+// ReadVarInt reads a Minecraft protocol VarInt.
+func ReadVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errors.New("VarInt is too big")
+}
+
+func writeString(w *bytes.Buffer, s string) {
+	WriteVarInt(w, int32(len(s)))
+	w.WriteString(s)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := ReadVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > r.Len() {
+		return "", errors.Errorf("invalid string length %d", length)
+	}
+	b := make([]byte, length)
+	_, _ = io.ReadFull(r, b)
+	return string(b), nil
+}
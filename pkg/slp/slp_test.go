@@ -0,0 +1,101 @@
+package slp_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp/slptest"
+)
+
+func TestVarInt(t *testing.T) {
+	for _, tc := range []struct {
+		value   int32
+		encoded []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{255, []byte{0xff, 0x01}},
+		{25565, []byte{0xdd, 0xc7, 0x01}},
+		{2147483647, []byte{0xff, 0xff, 0xff, 0xff, 0x07}},
+		{-1, []byte{0xff, 0xff, 0xff, 0xff, 0x0f}},
+	} {
+		var b bytes.Buffer
+		slp.WriteVarInt(&b, tc.value)
+		assert.Equal(t, tc.encoded, b.Bytes(), "encoding %d", tc.value)
+
+		decoded, err := slp.ReadVarInt(bytes.NewReader(tc.encoded))
+		require.NoError(t, err)
+		assert.Equal(t, tc.value, decoded)
+	}
+
+	_, err := slp.ReadVarInt(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}))
+	assert.Error(t, err)
+}
+
+func TestPing(t *testing.T) {
+	expected := slp.Status{
+		Version: slp.Version{Name: "Paper 1.19.2", Protocol: 760},
+		Players: slp.Players{
+			Max:    20,
+			Online: 2,
+			Sample: []slp.Player{
+				{Name: "Player1", ID: "da6a1ae6-e2f5-4e32-9135-b82a9ef426a9"},
+				{Name: "Player2", ID: "880182d6-a0e3-44cd-a57c-8dc3799e92b8"},
+			},
+		},
+		Description: slp.Description{Text: "A Minecraft Server"},
+		Favicon:     "data:image/png;base64,iVBORw0KGgo=",
+	}
+	server := slptest.NewServer(expected)
+	defer server.Close()
+
+	status, err := slp.Ping(context.Background(), server.Addr)
+	require.NoError(t, err)
+	assert.Greater(t, status.Latency, time.Duration(0))
+	status.Latency = 0
+	assert.Equal(t, &expected, status)
+}
+
+func TestPingChatComponentDescription(t *testing.T) {
+	server := slptest.NewRawServer(`{
+		"version": {"name": "1.19.2", "protocol": 760},
+		"players": {"max": 20, "online": 0},
+		"description": {"text": "Hello ", "extra": [{"text": "world", "bold": true}, "!"]}
+	}`)
+	defer server.Close()
+
+	status, err := slp.Ping(context.Background(), server.Addr)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world!", status.Description.Text)
+	assert.Empty(t, status.Players.Sample)
+}
+
+func TestPingTimeout(t *testing.T) {
+	// A server that accepts connections but never says anything
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = slp.Ping(ctx, l.Addr().String())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
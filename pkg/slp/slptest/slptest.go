@@ -0,0 +1,115 @@
+// Package slptest provides an in-process server that answers Server List Pings, for use in tests in the spirit of
+// net/http/httptest.
+package slptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"sync"
+
+	"github.com/jameslaverack/kubernetes-minecraft-operator/pkg/slp"
+)
+
+// Server is a fake Minecraft server listening on a local port, that only knows how to respond to Server List Pings.
+type Server struct {
+	// Addr is the address the server is listening on, in host:port form.
+	Addr string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	response string
+}
+
+// NewServer starts a new server that responds with the given status.
+func NewServer(status slp.Status) *Server {
+	d, err := json.Marshal(status)
+	if err != nil {
+		panic("slptest: failed to marshal status: " + err.Error())
+	}
+	return NewRawServer(string(d))
+}
+
+// NewRawServer starts a new server that responds with the given JSON. This is useful to test status responses that
+// can't be produced from an slp.Status, like ones with chat components in them.
+func NewRawServer(statusJSON string) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("slptest: failed to listen: " + err.Error())
+	}
+	s := &Server{
+		Addr:     l.Addr().String(),
+		listener: l,
+		response: statusJSON,
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// SetStatus changes the status the server responds with.
+func (s *Server) SetStatus(status slp.Status) {
+	d, err := json.Marshal(status)
+	if err != nil {
+		panic("slptest: failed to marshal status: " + err.Error())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.response = string(d)
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// Handshake
+	if _, _, err := slp.ReadPacket(r); err != nil {
+		return
+	}
+	for {
+		id, payload, err := slp.ReadPacket(r)
+		if err != nil {
+			return
+		}
+		switch id {
+		case 0x00:
+			s.mu.Lock()
+			response := s.response
+			s.mu.Unlock()
+			var b bytes.Buffer
+			slp.WriteVarInt(&b, int32(len(response)))
+			b.WriteString(response)
+			if err := slp.WritePacket(conn, 0x00, b.Bytes()); err != nil {
+				return
+			}
+		case 0x01:
+			// Pong, and then the server hangs up
+			_ = slp.WritePacket(conn, 0x01, payload)
+			return
+		default:
+			return
+		}
+	}
+}
@@ -1,11 +1,230 @@
+// Package version understands Minecraft version identifiers, like "1.20.4", "1.20-pre1", and "23w13a".
 package version
 
-import "strings"
+import (
+	"regexp"
+	"strconv"
 
+	"github.com/pkg/errors"
+)
+
+// Kind classifies a Minecraft version.
+type Kind int
+
+const (
+	// KindRelease is a full release, e.g., 1.20.4.
+	KindRelease Kind = iota
+	// KindReleaseCandidate is a release candidate, e.g., 1.20-rc1.
+	KindReleaseCandidate
+	// KindPreRelease is a pre-release, e.g., 1.20-pre1.
+	KindPreRelease
+	// KindSnapshot is a development snapshot, either a weekly snapshot like 23w13a or, since Minecraft switched to
+	// year-based version numbers, a numbered one like 26.1-snapshot-1.
+	KindSnapshot
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindRelease:
+		return "release"
+	case KindReleaseCandidate:
+		return "release candidate"
+	case KindPreRelease:
+		return "pre-release"
+	case KindSnapshot:
+		return "snapshot"
+	default:
+		return "unknown"
+	}
+}
+
+// Version is a parsed Minecraft version.
+type Version struct {
+	// Major, Minor, and Patch are the release the version is for, e.g., 1, 20, and 4 for 1.20.4. A missing patch
+	// version is zero. These are all zero for weekly snapshots, which don't say which release they're for.
+	Major, Minor, Patch int
+	Kind                Kind
+	// Build is which pre-release, release candidate, or numbered snapshot of the release this is, e.g., 2 for
+	// 1.20-pre2.
+	Build int
+	// Year, Week, and Letter identify a weekly snapshot, e.g., 23, 13, and 'a' for 23w13a.
+	Year, Week int
+	Letter     byte
+
+	original string
+}
+
+var (
+	weeklySnapshot = regexp.MustCompile(`^(\d{2})w(\d{2})([a-z])$`)
+	// Older pre-releases were written like "1.14 Pre-Release 1", and year-based ones are written like "26.1-pre-1".
+	numbered = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(?:-(pre|rc|snapshot)-?(\d+)| Pre-Release (\d+))?$`)
+)
+
+// Parse parses a Minecraft version.
+func Parse(s string) (Version, error) {
+	if m := weeklySnapshot.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		return Version{Kind: KindSnapshot, Year: year, Week: week, Letter: m[3][0], original: s}, nil
+	}
+
+	m := numbered.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, errors.Errorf("%q is not a Minecraft version", s)
+	}
+	v := Version{original: s}
+	var err error
+	if v.Major, err = strconv.Atoi(m[1]); err != nil {
+		return Version{}, errors.Wrapf(err, "%q is not a Minecraft version", s)
+	}
+	if v.Minor, err = strconv.Atoi(m[2]); err != nil {
+		return Version{}, errors.Wrapf(err, "%q is not a Minecraft version", s)
+	}
+	if m[3] != "" {
+		if v.Patch, err = strconv.Atoi(m[3]); err != nil {
+			return Version{}, errors.Wrapf(err, "%q is not a Minecraft version", s)
+		}
+	}
+
+	build := m[5]
+	switch {
+	case m[4] == "pre":
+		v.Kind = KindPreRelease
+	case m[4] == "rc":
+		v.Kind = KindReleaseCandidate
+	case m[4] == "snapshot":
+		v.Kind = KindSnapshot
+	case m[6] != "":
+		v.Kind = KindPreRelease
+		build = m[6]
+	}
+	if build != "" {
+		if v.Build, err = strconv.Atoi(build); err != nil {
+			return Version{}, errors.Wrapf(err, "%q is not a Minecraft version", s)
+		}
+	}
+	return v, nil
+}
+
+// MustParse is like Parse, but panics if the version can't be parsed. It's intended for versions known at compile
+// time.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (v Version) String() string {
+	return v.original
+}
+
+// IsRelease is true for full releases, and false for snapshots, pre-releases, and release candidates.
+func (v Version) IsRelease() bool {
+	return v.Kind == KindRelease
+}
+
+// IsWeeklySnapshot is true for snapshots like 23w13a.
+func (v Version) IsWeeklySnapshot() bool {
+	return v.Kind == KindSnapshot && v.Year != 0
+}
+
+// MinorVersion is the major and minor version, e.g., "1.20" for 1.20.4 or 1.20-pre1. Weekly snapshots don't have one,
+// so they're returned as-is.
+func (v Version) MinorVersion() string {
+	if v.IsWeeklySnapshot() {
+		return v.original
+	}
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// Compare returns -1, 0, or 1 if v is older than, the same as, or newer than o. Snapshots, pre-releases, and release
+// candidates are older than the release they're for. Weekly snapshots don't say which release they're for, so they can
+// only be compared to other weekly snapshots, and an error is returned otherwise.
+func (v Version) Compare(o Version) (int, error) {
+	if v.IsWeeklySnapshot() != o.IsWeeklySnapshot() {
+		return 0, errors.Errorf("cannot compare weekly snapshot with numbered version (%s and %s)", v, o)
+	}
+	if v.IsWeeklySnapshot() {
+		return compareInts(v.Year, o.Year, v.Week, o.Week, int(v.Letter), int(o.Letter)), nil
+	}
+	// Kinds are ordered newest first, so they're compared the other way around
+	return compareInts(v.Major, o.Major, v.Minor, o.Minor, v.Patch, o.Patch, int(o.Kind), int(v.Kind), v.Build, o.Build), nil
+}
+
+// compareInts compares pairs of numbers in turn, returning the result of the first pair that differ.
+func compareInts(pairs ...int) int {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i] < pairs[i+1] {
+			return -1
+		}
+		if pairs[i] > pairs[i+1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseMinorVersion returns the major and minor version of a Minecraft version, e.g., "1.19" for "1.19.2". Versions
+// that can't be parsed are returned as-is.
 func ParseMinorVersion(version string) string {
-	if strings.Count(version, ".") > 1 {
-		splits := strings.Split(version, ".")
-		return splits[0] + "." + splits[1]
+	v, err := Parse(version)
+	if err != nil {
+		return version
+	}
+	return v.MinorVersion()
+}
+
+// Compare compares two Minecraft versions, e.g., "1.19" and "1.19.2", returning -1, 0, or 1 if a is older than, the
+// same as, or newer than b. A missing patch version is treated as zero. An error is returned if either can't be parsed,
+// or if they can't be compared.
+func Compare(a, b string) (int, error) {
+	av, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return av.Compare(bv)
+}
+
+// javaVersions lists the first Minecraft version to need each version of Java, newest first, along with the first
+// weekly snapshot to need it. The pre-releases and release candidates of a version need the same Java as the release,
+// and sort before it, so the first pre-release is what we compare against.
+var javaVersions = []struct {
+	minecraftVersion Version
+	snapshot         Version
+	javaVersion      int
+}{
+	// 26.1 is after weekly snapshots were replaced with numbered ones
+	{MustParse("26.1-snapshot-1"), Version{}, 25},
+	{MustParse("1.20.5-pre1"), MustParse("24w14a"), 21},
+	// 1.17 needs Java 16, which is no longer supported, so we use the next LTS release
+	{MustParse("1.17-pre1"), MustParse("21w19a"), 17},
+}
+
+// JavaVersion returns the major version of Java to run a Minecraft version with. Versions we can't parse are assumed to
+// be recent and get the newest Java.
+func JavaVersion(minecraftVersion string) int {
+	v, err := Parse(minecraftVersion)
+	if err != nil {
+		return javaVersions[0].javaVersion
+	}
+	for _, j := range javaVersions {
+		since := j.minecraftVersion
+		if v.IsWeeklySnapshot() {
+			if j.snapshot == (Version{}) {
+				continue
+			}
+			since = j.snapshot
+		}
+		if c, err := v.Compare(since); err == nil && c >= 0 {
+			return j.javaVersion
+		}
 	}
-	return version
+	// Older versions, and many of the mods for them, only work properly on Java 8
+	return 8
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseMinorVersion(t *testing.T) {
@@ -13,4 +14,120 @@ func TestParseMinorVersion(t *testing.T) {
 	t.Run("patch", func(t *testing.T) {
 		assert.Equal(t, "1.19", ParseMinorVersion("1.19.1"))
 	})
+	t.Run("pre-release", func(t *testing.T) {
+		assert.Equal(t, "1.20", ParseMinorVersion("1.20-pre1"))
+	})
+	t.Run("snapshot", func(t *testing.T) {
+		assert.Equal(t, "23w13a", ParseMinorVersion("23w13a"))
+	})
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.19", "1.19", 0},
+		{"1.19", "1.19.0", 0},
+		{"1.19.1", "1.19", 1},
+		{"1.18.2", "1.19", -1},
+		{"1.9", "1.19", -1},
+		{"1.19.10", "1.19.2", 1},
+		{"1.20-pre1", "1.20", -1},
+		{"1.20-pre2", "1.20-pre1", 1},
+		{"1.20-rc1", "1.20-pre7", 1},
+		{"1.20-rc1", "1.19.4", 1},
+		{"1.14 Pre-Release 5", "1.14-pre5", 0},
+		{"26.1-snapshot-2", "26.1-pre-1", -1},
+		{"26.1-snapshot-2", "1.21.10", 1},
+		{"23w13a", "23w12b", 1},
+		{"23w13a", "23w13b", -1},
+		{"22w45a", "23w03a", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			c, err := Compare(tt.a, tt.b)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, c)
+		})
+	}
+
+	t.Run("weekly snapshot and release", func(t *testing.T) {
+		_, err := Compare("22w45a", "1.19")
+		assert.Error(t, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected Version
+	}{
+		{"1.20.4", Version{Major: 1, Minor: 20, Patch: 4, Kind: KindRelease}},
+		{"1.20", Version{Major: 1, Minor: 20, Kind: KindRelease}},
+		{"1.20-pre1", Version{Major: 1, Minor: 20, Kind: KindPreRelease, Build: 1}},
+		{"1.14.4 Pre-Release 2", Version{Major: 1, Minor: 14, Patch: 4, Kind: KindPreRelease, Build: 2}},
+		{"1.20.2-rc2", Version{Major: 1, Minor: 20, Patch: 2, Kind: KindReleaseCandidate, Build: 2}},
+		{"23w13a", Version{Kind: KindSnapshot, Year: 23, Week: 13, Letter: 'a'}},
+		{"26.1", Version{Major: 26, Minor: 1, Kind: KindRelease}},
+		{"26.1-snapshot-3", Version{Major: 26, Minor: 1, Kind: KindSnapshot, Build: 3}},
+		{"26.1-pre-2", Version{Major: 26, Minor: 1, Kind: KindPreRelease, Build: 2}},
+		{"26.1-rc-1", Version{Major: 26, Minor: 1, Kind: KindReleaseCandidate, Build: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			v, err := Parse(tt.version)
+			require.NoError(t, err)
+			tt.expected.original = tt.version
+			assert.Equal(t, tt.expected, v)
+			assert.Equal(t, tt.version, v.String())
+		})
+	}
+
+	for _, invalid := range []string{"", "1", "latest", "1.20-beta1", "23w13", "1.x"} {
+		t.Run(invalid, func(t *testing.T) {
+			_, err := Parse(invalid)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKind(t *testing.T) {
+	assert.True(t, MustParse("1.20").IsRelease())
+	assert.False(t, MustParse("1.20-rc1").IsRelease())
+	assert.True(t, MustParse("23w13a").IsWeeklySnapshot())
+	assert.False(t, MustParse("26.1-snapshot-1").IsWeeklySnapshot())
+	assert.Equal(t, "pre-release", MustParse("1.20-pre1").Kind.String())
+}
+
+func TestJavaVersion(t *testing.T) {
+	tests := []struct {
+		minecraftVersion string
+		expected         int
+	}{
+		{"1.12.2", 8},
+		{"1.16.5", 8},
+		{"1.16.5-rc1", 8},
+		{"1.17-pre1", 17},
+		{"1.17-rc1", 17},
+		{"1.17", 17},
+		{"1.18.2", 17},
+		{"1.20.4", 17},
+		{"1.20.4-rc1", 17},
+		{"1.20.5-pre1", 21},
+		{"1.20.5-rc1", 21},
+		{"1.20.5", 21},
+		{"1.21.1", 21},
+		{"26.1", 25},
+		{"26.1-snapshot-1", 25},
+		{"24w14a", 21},
+		{"22w11a", 17},
+		{"20w45a", 8},
+		{"not a version", 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.minecraftVersion, func(t *testing.T) {
+			assert.Equal(t, tt.expected, JavaVersion(tt.minecraftVersion))
+		})
+	}
 }